@@ -32,7 +32,7 @@ func main() {
 	}
 	defer func() {
 		if closeErr := bot.Close(); closeErr != nil {
-			logger.Warn("failed closing docker client", "err", closeErr.Error())
+			logger.Warn("failed closing clients", "err", closeErr.Error())
 		}
 	}()
 
@@ -0,0 +1,261 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	filePrefix = "palworld-"
+	fileSuffix = ".tar.gz"
+	idLayout   = "20060102-150405"
+)
+
+type Archive struct {
+	ID      string
+	Path    string
+	Size    int64
+	Created time.Time
+}
+
+type Retention struct {
+	KeepLast   int
+	KeepDaily  int
+	KeepWeekly int
+
+	// loc is where days and weeks start; nil means the operator's local
+	// time zone.
+	loc *time.Location
+}
+
+type Manager struct {
+	dir       string
+	retention Retention
+	now       func() time.Time
+}
+
+func New(dir string, retention Retention) *Manager {
+	return &Manager{dir: dir, retention: retention, now: time.Now}
+}
+
+func (m *Manager) Dir() string {
+	return m.dir
+}
+
+// Create archives sourceDir into a timestamped tar.gz. Entries are stored
+// relative to the parent of sourceDir, so the "Saved" directory itself is
+// the archive root.
+func (m *Manager) Create(ctx context.Context, sourceDir string) (Archive, error) {
+	info, err := os.Stat(sourceDir)
+	if err != nil {
+		return Archive{}, fmt.Errorf("backup source: %w", err)
+	}
+	if !info.IsDir() {
+		return Archive{}, fmt.Errorf("backup source %s is not a directory", sourceDir)
+	}
+	if err := os.MkdirAll(m.dir, 0o700); err != nil {
+		return Archive{}, fmt.Errorf("create backup directory: %w", err)
+	}
+
+	created := m.now().UTC().Truncate(time.Second)
+	id := created.Format(idLayout)
+	target := filepath.Join(m.dir, filePrefix+id+fileSuffix)
+	if _, err := os.Stat(target); err == nil {
+		return Archive{}, fmt.Errorf("backup %s already exists", id)
+	}
+
+	tmp := target + ".tmp"
+	if err := writeArchive(ctx, tmp, sourceDir); err != nil {
+		os.Remove(tmp)
+		return Archive{}, err
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		os.Remove(tmp)
+		return Archive{}, fmt.Errorf("finalize backup: %w", err)
+	}
+
+	stat, err := os.Stat(target)
+	if err != nil {
+		return Archive{}, err
+	}
+	return Archive{ID: id, Path: target, Size: stat.Size(), Created: created}, nil
+}
+
+func writeArchive(ctx context.Context, target, sourceDir string) error {
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("create archive: %w", err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	base := filepath.Dir(filepath.Clean(sourceDir))
+	err = filepath.WalkDir(sourceDir, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() && !info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(base, path)
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(tw, src)
+		src.Close()
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("write archive: %w", err)
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("write archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("write archive: %w", err)
+	}
+	return f.Sync()
+}
+
+// List returns the archives in the backup directory, newest first.
+func (m *Manager) List() ([]Archive, error) {
+	entries, err := os.ReadDir(m.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("list backups: %w", err)
+	}
+
+	var archives []Archive
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, filePrefix) || !strings.HasSuffix(name, fileSuffix) {
+			continue
+		}
+		id := strings.TrimSuffix(strings.TrimPrefix(name, filePrefix), fileSuffix)
+		created, err := time.Parse(idLayout, id)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		archives = append(archives, Archive{ID: id, Path: filepath.Join(m.dir, name), Size: info.Size(), Created: created})
+	}
+	sort.Slice(archives, func(i, j int) bool {
+		return archives[i].Created.After(archives[j].Created)
+	})
+	return archives, nil
+}
+
+func (m *Manager) Get(id string) (Archive, error) {
+	archives, err := m.List()
+	if err != nil {
+		return Archive{}, err
+	}
+	for _, archive := range archives {
+		if archive.ID == id {
+			return archive, nil
+		}
+	}
+	return Archive{}, fmt.Errorf("backup %q not found", id)
+}
+
+// Prune deletes archives not kept by the retention policy and returns them.
+func (m *Manager) Prune() ([]Archive, error) {
+	archives, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+	keep := m.retention.keep(archives)
+
+	var removed []Archive
+	for _, archive := range archives {
+		if keep[archive.ID] {
+			continue
+		}
+		if err := os.Remove(archive.Path); err != nil {
+			return removed, fmt.Errorf("remove backup %s: %w", archive.ID, err)
+		}
+		removed = append(removed, archive)
+	}
+	return removed, nil
+}
+
+// keep selects archives to retain from a newest-first list: the KeepLast
+// newest, plus the newest archive of each of the KeepDaily most recent days
+// and KeepWeekly most recent ISO weeks that have a backup. Days and weeks
+// are local, so they end at the operator's midnight rather than UTC's.
+func (r Retention) keep(archives []Archive) map[string]bool {
+	loc := r.loc
+	if loc == nil {
+		loc = time.Local
+	}
+	keep := make(map[string]bool)
+	for i, archive := range archives {
+		if i < r.KeepLast {
+			keep[archive.ID] = true
+		}
+	}
+	keepNewestPer(archives, r.KeepDaily, keep, func(t time.Time) string {
+		return t.In(loc).Format("2006-01-02")
+	})
+	keepNewestPer(archives, r.KeepWeekly, keep, func(t time.Time) string {
+		year, week := t.In(loc).ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	})
+	return keep
+}
+
+func keepNewestPer(archives []Archive, limit int, keep map[string]bool, bucket func(time.Time) string) {
+	seen := make(map[string]bool)
+	for _, archive := range archives {
+		if len(seen) >= limit {
+			return
+		}
+		key := bucket(archive.Created)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		keep[archive.ID] = true
+	}
+}
@@ -0,0 +1,197 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestCreateAndList(t *testing.T) {
+	root := t.TempDir()
+	saved := filepath.Join(root, "Pal", "Saved")
+	writeFile(t, filepath.Join(saved, "SaveGames", "0", "world", "Level.sav"), "level")
+	writeFile(t, filepath.Join(saved, "Config", "PalWorldSettings.ini"), "settings")
+
+	m := New(filepath.Join(root, "backups"), Retention{KeepLast: 10})
+	m.now = func() time.Time { return time.Date(2024, 3, 1, 18, 30, 0, 0, time.UTC) }
+
+	archive, err := m.Create(context.Background(), saved)
+	if err != nil {
+		t.Fatalf("Create(): %v", err)
+	}
+	if archive.ID != "20240301-183000" || archive.Size == 0 {
+		t.Fatalf("Create() got %+v", archive)
+	}
+	if _, err := m.Create(context.Background(), saved); err == nil {
+		t.Fatal("Create() twice in the same second expected error")
+	}
+
+	got := archiveNames(t, archive.Path)
+	want := []string{
+		"Saved/",
+		"Saved/Config/",
+		"Saved/Config/PalWorldSettings.ini",
+		"Saved/SaveGames/",
+		"Saved/SaveGames/0/",
+		"Saved/SaveGames/0/world/",
+		"Saved/SaveGames/0/world/Level.sav",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("archive entries got %v want %v", got, want)
+	}
+
+	list, err := m.List()
+	if err != nil {
+		t.Fatalf("List(): %v", err)
+	}
+	if len(list) != 1 || list[0].ID != archive.ID {
+		t.Fatalf("List() got %+v", list)
+	}
+	if _, err := m.Get("nope"); err == nil {
+		t.Fatal("Get() of unknown id expected error")
+	}
+}
+
+func TestCreateMissingSource(t *testing.T) {
+	m := New(t.TempDir(), Retention{})
+	if _, err := m.Create(context.Background(), filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Fatal("Create() with missing source expected error")
+	}
+}
+
+func TestRetentionKeep(t *testing.T) {
+	// Newest first: two per day on Mon 2024-01-15 .. Fri 2024-01-05 (two weeks).
+	var archives []Archive
+	for day := 15; day >= 5; day-- {
+		for _, hour := range []int{18, 6} {
+			created := time.Date(2024, 1, day, hour, 0, 0, 0, time.UTC)
+			archives = append(archives, Archive{ID: created.Format(idLayout), Created: created})
+		}
+	}
+
+	tests := []struct {
+		name      string
+		retention Retention
+		want      []string
+	}{
+		{
+			name:      "keep last only",
+			retention: Retention{KeepLast: 3},
+			want:      []string{"20240114-180000", "20240115-060000", "20240115-180000"},
+		},
+		{
+			name:      "daily keeps newest of each day",
+			retention: Retention{KeepDaily: 2},
+			want:      []string{"20240114-180000", "20240115-180000"},
+		},
+		{
+			name:      "weekly keeps newest of each iso week",
+			retention: Retention{KeepWeekly: 3},
+			want:      []string{"20240107-180000", "20240114-180000", "20240115-180000"},
+		},
+		{
+			name:      "combined policies overlap",
+			retention: Retention{KeepLast: 1, KeepDaily: 1, KeepWeekly: 2},
+			want:      []string{"20240114-180000", "20240115-180000"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.retention.loc = time.UTC
+			keep := tt.retention.keep(archives)
+			got := make([]string, 0, len(keep))
+			for id := range keep {
+				got = append(got, id)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("keep() got %v want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetentionKeepUsesLocalDays(t *testing.T) {
+	// 23:30 and 22:00 UTC on Jan 14 are already Jan 15 in UTC+3, the same
+	// day as 09:00 UTC on Jan 15.
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	var archives []Archive
+	for _, created := range []time.Time{
+		time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 14, 23, 30, 0, 0, time.UTC),
+		time.Date(2024, 1, 14, 22, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 14, 12, 0, 0, 0, time.UTC),
+	} {
+		archives = append(archives, Archive{ID: created.Format(idLayout), Created: created})
+	}
+
+	keep := Retention{KeepDaily: 2, loc: loc}.keep(archives)
+	got := make([]string, 0, len(keep))
+	for id := range keep {
+		got = append(got, id)
+	}
+	sort.Strings(got)
+	if want := []string{"20240114-120000", "20240115-090000"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("keep() got %v want %v", got, want)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	for _, id := range []string{"20240101-000000", "20240102-000000", "20240103-000000"} {
+		writeFile(t, filepath.Join(dir, filePrefix+id+fileSuffix), "x")
+	}
+	writeFile(t, filepath.Join(dir, "unrelated.txt"), "x")
+
+	m := New(dir, Retention{KeepLast: 2})
+	removed, err := m.Prune()
+	if err != nil {
+		t.Fatalf("Prune(): %v", err)
+	}
+	if len(removed) != 1 || removed[0].ID != "20240101-000000" {
+		t.Fatalf("Prune() removed %+v", removed)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "unrelated.txt")); err != nil {
+		t.Fatalf("Prune() touched unrelated files: %v", err)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func archiveNames(t *testing.T, path string) []string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	var names []string
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			break
+		}
+		names = append(names, header.Name)
+	}
+	return names
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package backup
+
+import "os"
+
+func fileOwner(os.FileInfo) (owner, bool) {
+	return owner{}, false
+}
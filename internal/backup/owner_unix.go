@@ -0,0 +1,16 @@
+//go:build unix
+
+package backup
+
+import (
+	"os"
+	"syscall"
+)
+
+func fileOwner(info os.FileInfo) (owner, bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return owner{}, false
+	}
+	return owner{uid: int(st.Uid), gid: int(st.Gid)}, true
+}
@@ -0,0 +1,191 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Restored describes the outcome of Replace.
+type Restored struct {
+	// Snapshot is the safety snapshot of the replaced save directory; it is
+	// empty if there was nothing to replace.
+	Snapshot string
+	// OwnerErr is set when the restored files could not be handed to the
+	// owner of the save they replace. The restore itself went through, but
+	// the game server may be unable to write its saves.
+	OwnerErr error
+}
+
+// Replace swaps targetDir for the contents of archive. The current directory
+// is renamed to a sibling safety snapshot, whose path is returned so the
+// caller can roll back later. If extraction fails, the snapshot is moved
+// back before returning; only if that fails too is it returned with the
+// error. Restored files get the owner of the directory they replace (or of
+// its parent), as far as the bot is allowed to chown.
+func (m *Manager) Replace(ctx context.Context, archive Archive, targetDir string) (Restored, error) {
+	targetDir = filepath.Clean(targetDir)
+	chown := &chowner{}
+	if info, err := os.Stat(targetDir); err == nil {
+		chown.owner, chown.ok = fileOwner(info)
+	} else if info, err := os.Stat(filepath.Dir(targetDir)); err == nil {
+		chown.owner, chown.ok = fileOwner(info)
+	}
+
+	snapshot := targetDir + ".pre-restore-" + m.now().UTC().Format(idLayout)
+	if err := os.Rename(targetDir, snapshot); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return Restored{}, fmt.Errorf("move current save aside: %w", err)
+		}
+		snapshot = ""
+	}
+
+	if err := extract(ctx, archive.Path, targetDir, chown); err != nil {
+		if rollbackErr := Rollback(targetDir, snapshot); rollbackErr != nil {
+			return Restored{Snapshot: snapshot}, fmt.Errorf("%w (rollback failed: %v)", err, rollbackErr)
+		}
+		return Restored{}, err
+	}
+	return Restored{Snapshot: snapshot, OwnerErr: chown.err()}, nil
+}
+
+// Rollback discards targetDir and moves the safety snapshot from Replace
+// back into place.
+func Rollback(targetDir, snapshot string) error {
+	if err := os.RemoveAll(targetDir); err != nil {
+		return fmt.Errorf("remove restored save: %w", err)
+	}
+	if snapshot == "" {
+		return nil
+	}
+	if err := os.Rename(snapshot, targetDir); err != nil {
+		return fmt.Errorf("move safety snapshot back: %w", err)
+	}
+	return nil
+}
+
+// extract unpacks an archive written by Create into targetDir, dropping the
+// archive's root directory so it does not matter what the source was called.
+func extract(ctx context.Context, archivePath, targetDir string, chown *chowner) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("open archive: %w", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("read archive: %w", err)
+	}
+	defer gz.Close()
+
+	if err := os.MkdirAll(targetDir, 0o755); err != nil {
+		return fmt.Errorf("create save directory: %w", err)
+	}
+	chown.chown(targetDir)
+	tr := tar.NewReader(gz)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read archive: %w", err)
+		}
+
+		rel, err := entryPath(header.Name)
+		if err != nil {
+			return err
+		}
+		if rel == "" {
+			continue
+		}
+		dest := filepath.Join(targetDir, filepath.FromSlash(rel))
+		mode := os.FileMode(header.Mode).Perm()
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(dest, mode|0o700); err != nil {
+				return fmt.Errorf("extract %s: %w", rel, err)
+			}
+			chown.chown(dest)
+		case tar.TypeReg:
+			if err := writeEntry(dest, mode, tr); err != nil {
+				return fmt.Errorf("extract %s: %w", rel, err)
+			}
+			chown.chown(dest)
+		default:
+			return fmt.Errorf("archive entry %s has unsupported type %q", header.Name, header.Typeflag)
+		}
+	}
+}
+
+// entryPath strips the archive root from name and rejects anything that
+// would land outside the target directory.
+func entryPath(name string) (string, error) {
+	cleaned := path.Clean(strings.TrimPrefix(name, "./"))
+	if path.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("archive entry %q escapes the save directory", name)
+	}
+	_, rel, found := strings.Cut(cleaned, "/")
+	if !found {
+		return "", nil
+	}
+	return rel, nil
+}
+
+func writeEntry(dest string, mode os.FileMode, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_EXCL|os.O_WRONLY, mode|0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+type owner struct {
+	uid, gid int
+}
+
+// chowner hands extracted entries to the owner of the save they replace.
+// Failures do not stop the restore; the first one is kept for the report.
+type chowner struct {
+	owner  owner
+	ok     bool
+	failed int
+	first  error
+}
+
+func (c *chowner) chown(path string) {
+	if !c.ok {
+		return
+	}
+	if err := os.Lchown(path, c.owner.uid, c.owner.gid); err != nil {
+		if c.failed == 0 {
+			c.first = err
+		}
+		c.failed++
+	}
+}
+
+func (c *chowner) err() error {
+	if c.failed == 0 {
+		return nil
+	}
+	return fmt.Errorf("could not give %d restored entries to uid %d gid %d: %w", c.failed, c.owner.uid, c.owner.gid, c.first)
+}
@@ -0,0 +1,181 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReplaceAndRollback(t *testing.T) {
+	root := t.TempDir()
+	saved := filepath.Join(root, "Saved")
+	level := filepath.Join(saved, "SaveGames", "0", "world", "Level.sav")
+	writeFile(t, level, "old")
+
+	m := New(filepath.Join(root, "backups"), Retention{KeepLast: 10})
+	m.now = func() time.Time { return time.Date(2024, 3, 1, 18, 30, 0, 0, time.UTC) }
+	archive, err := m.Create(context.Background(), saved)
+	if err != nil {
+		t.Fatalf("Create(): %v", err)
+	}
+
+	writeFile(t, level, "new")
+	writeFile(t, filepath.Join(saved, "extra.txt"), "extra")
+	m.now = func() time.Time { return time.Date(2024, 3, 2, 9, 0, 0, 0, time.UTC) }
+
+	restored, err := m.Replace(context.Background(), archive, saved)
+	if err != nil {
+		t.Fatalf("Replace(): %v", err)
+	}
+	if restored.OwnerErr != nil {
+		t.Fatalf("Replace() owner err %v", restored.OwnerErr)
+	}
+	snapshot := restored.Snapshot
+	if snapshot != saved+".pre-restore-20240302-090000" {
+		t.Fatalf("Replace() snapshot got %q", snapshot)
+	}
+	assertContent(t, level, "old")
+	if _, err := os.Stat(filepath.Join(saved, "extra.txt")); !os.IsNotExist(err) {
+		t.Fatalf("extra.txt should not survive a restore, stat err %v", err)
+	}
+	assertContent(t, filepath.Join(snapshot, "SaveGames", "0", "world", "Level.sav"), "new")
+
+	if err := Rollback(saved, snapshot); err != nil {
+		t.Fatalf("Rollback(): %v", err)
+	}
+	assertContent(t, level, "new")
+	assertContent(t, filepath.Join(saved, "extra.txt"), "extra")
+	if _, err := os.Stat(snapshot); !os.IsNotExist(err) {
+		t.Fatalf("snapshot should be gone after rollback, stat err %v", err)
+	}
+}
+
+func TestReplaceRollsBackOnBadArchive(t *testing.T) {
+	root := t.TempDir()
+	saved := filepath.Join(root, "Saved")
+	writeFile(t, filepath.Join(saved, "Level.sav"), "current")
+
+	bad := filepath.Join(root, "bad.tar.gz")
+	writeTarGz(t, bad, [][2]string{
+		{"Saved/Level.sav", "restored"},
+		{"Saved/../../evil.txt", "evil"},
+	})
+
+	m := New(filepath.Join(root, "backups"), Retention{KeepLast: 10})
+	if _, err := m.Replace(context.Background(), Archive{ID: "bad", Path: bad}, saved); err == nil {
+		t.Fatal("Replace() with traversal entry expected error")
+	}
+	assertContent(t, filepath.Join(saved, "Level.sav"), "current")
+	if _, err := os.Stat(filepath.Join(root, "evil.txt")); !os.IsNotExist(err) {
+		t.Fatalf("evil.txt must not be written, stat err %v", err)
+	}
+	matches, _ := filepath.Glob(saved + ".pre-restore-*")
+	if len(matches) != 0 {
+		t.Fatalf("snapshot left behind after failed restore: %v", matches)
+	}
+}
+
+func TestReplaceKeepsSaveOwner(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("chown needs root")
+	}
+	root := t.TempDir()
+	saved := filepath.Join(root, "Saved")
+	level := filepath.Join(saved, "SaveGames", "Level.sav")
+	writeFile(t, level, "old")
+
+	m := New(filepath.Join(root, "backups"), Retention{KeepLast: 10})
+	archive, err := m.Create(context.Background(), saved)
+	if err != nil {
+		t.Fatalf("Create(): %v", err)
+	}
+	if err := os.Chown(saved, 1234, 1234); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := m.Replace(context.Background(), archive, saved)
+	if err != nil {
+		t.Fatalf("Replace(): %v", err)
+	}
+	if restored.OwnerErr != nil {
+		t.Fatalf("Replace() owner err %v", restored.OwnerErr)
+	}
+	for _, path := range []string{saved, filepath.Dir(level), level} {
+		info, err := os.Lstat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, ok := fileOwner(info)
+		if !ok {
+			t.Skip("file owners are not available on this platform")
+		}
+		if got != (owner{uid: 1234, gid: 1234}) {
+			t.Fatalf("%s owner got %+v want 1234:1234", path, got)
+		}
+	}
+}
+
+func TestEntryPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "Saved/", want: ""},
+		{name: "Saved/SaveGames/0/Level.sav", want: "SaveGames/0/Level.sav"},
+		{name: "./Saved/Config/", want: "Config"},
+		{name: "/etc/passwd", wantErr: true},
+		{name: "../Saved/x", wantErr: true},
+		{name: "Saved/../../x", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := entryPath(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("entryPath(%q) err = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Fatalf("entryPath(%q) got %q want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func assertContent(t *testing.T, path, want string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	if string(data) != want {
+		t.Fatalf("%s got %q want %q", path, data, want)
+	}
+}
+
+func writeTarGz(t *testing.T, path string, files [][2]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, file := range files {
+		name, content := file[0], file[1]
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,113 @@
+package chat
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	parser, err := NewParser(DefaultPatterns)
+	if err != nil {
+		t.Fatalf("NewParser(): %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		line   string
+		want   Message
+		wantOK bool
+	}{
+		{
+			name:   "global chat",
+			line:   "[2024.09.12-10:23:45:123][ 12]LogPalServer: [Chat::Global]['Alice' (UserId=steam_76561198000000000, IP=1.2.3.4)]: hello there",
+			want:   Message{Name: "Alice", Text: "hello there"},
+			wantOK: true,
+		},
+		{
+			name:   "guild chat",
+			line:   "[Chat::Guild]['Bob' (UserId=steam_1, IP=5.6.7.8)]: gg\r\n",
+			want:   Message{Name: "Bob", Text: "gg"},
+			wantOK: true,
+		},
+		{
+			name:   "bracket format",
+			line:   "[CHAT] <Zoë> こんにちは",
+			want:   Message{Name: "Zoë", Text: "こんにちは"},
+			wantOK: true,
+		},
+		{name: "join line", line: "[2024.09.12-10:23:45:123] Alice joined the server. (User id: steam_1)"},
+		{name: "empty message", line: "[CHAT] <Alice> "},
+		{name: "blank", line: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parser.Parse(tt.line)
+			if ok != tt.wantOK || got != tt.want {
+				t.Fatalf("Parse(%q) got %+v, %v want %+v, %v", tt.line, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestNewParserValidatesPatterns(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+	}{
+		{name: "none", patterns: nil},
+		{name: "invalid regexp", patterns: []string{`(`}},
+		{name: "missing groups", patterns: []string{`\[CHAT\] (.*)`}},
+	}
+	for _, tt := range tests {
+		if _, err := NewParser(tt.patterns); err == nil {
+			t.Fatalf("%s: NewParser(%q) expected error", tt.name, tt.patterns)
+		}
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	l := NewLimiter(6, 2)
+
+	if !l.Allow(start) || !l.Allow(start) {
+		t.Fatal("burst of 2 should be allowed")
+	}
+	if l.Allow(start.Add(time.Second)) {
+		t.Fatal("third message within a second should be limited")
+	}
+	// 6 per minute refills one token every 10s.
+	if !l.Allow(start.Add(11 * time.Second)) {
+		t.Fatal("token should be refilled after 10s")
+	}
+	if l.Allow(start.Add(12 * time.Second)) {
+		t.Fatal("only one token should have been refilled")
+	}
+	if !l.Allow(start.Add(10*time.Minute)) || !l.Allow(start.Add(10*time.Minute)) {
+		t.Fatal("bucket should refill up to the burst")
+	}
+	if l.Allow(start.Add(10 * time.Minute)) {
+		t.Fatal("refill must be capped at the burst")
+	}
+}
+
+func TestEcho(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	e := NewEcho(30 * time.Second)
+
+	e.Remember("Alice: hello  world", now)
+	if !e.Seen("alice: hello world", now.Add(time.Second)) {
+		t.Fatal("echo of a sent message should be recognised")
+	}
+	if e.Seen("Alice: hello world", now.Add(2*time.Second)) {
+		t.Fatal("an echo is only swallowed once")
+	}
+
+	e.Remember("bye", now)
+	if e.Seen("bye", now.Add(time.Minute)) {
+		t.Fatal("echo should expire after the ttl")
+	}
+	if e.Seen("something else", now) {
+		t.Fatal("unrelated text is not an echo")
+	}
+}
@@ -0,0 +1,48 @@
+package chat
+
+import (
+	"strings"
+	"time"
+)
+
+// Echo remembers messages the bridge sent into the game so the same text
+// showing up in the game log is not relayed back to Matrix.
+type Echo struct {
+	ttl  time.Duration
+	sent map[string]time.Time
+}
+
+func NewEcho(ttl time.Duration) *Echo {
+	return &Echo{ttl: ttl, sent: make(map[string]time.Time)}
+}
+
+func (e *Echo) Remember(text string, now time.Time) {
+	e.expire(now)
+	e.sent[normalize(text)] = now.Add(e.ttl)
+}
+
+// Seen reports whether text was sent by us recently and forgets it, so a
+// player repeating the same words later is still relayed.
+func (e *Echo) Seen(text string, now time.Time) bool {
+	e.expire(now)
+	key := normalize(text)
+	if _, ok := e.sent[key]; !ok {
+		return false
+	}
+	delete(e.sent, key)
+	return true
+}
+
+func (e *Echo) expire(now time.Time) {
+	for key, until := range e.sent {
+		if now.After(until) {
+			delete(e.sent, key)
+		}
+	}
+}
+
+// normalize ignores the whitespace and case changes the RCON encoding and
+// the game may apply on the way through; no-break spaces count as spaces.
+func normalize(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
@@ -0,0 +1,34 @@
+package chat
+
+import "time"
+
+// Limiter is a token bucket: it allows burst messages at once and refills
+// at perMinute messages per minute.
+type Limiter struct {
+	perMinute int
+	burst     int
+	tokens    float64
+	last      time.Time
+}
+
+func NewLimiter(perMinute, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{perMinute: perMinute, burst: burst, tokens: float64(burst)}
+}
+
+func (l *Limiter) Allow(now time.Time) bool {
+	if !l.last.IsZero() && now.After(l.last) {
+		l.tokens += now.Sub(l.last).Minutes() * float64(l.perMinute)
+		if l.tokens > float64(l.burst) {
+			l.tokens = float64(l.burst)
+		}
+	}
+	l.last = now
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
@@ -0,0 +1,66 @@
+package chat
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultPatterns match the chat lines Palworld writes to its log, e.g.
+//
+//	[2024.09.12-10:23:45:123][ 12]LogPalServer: [Chat::Global]['Alice' (UserId=steam_7656..., IP=1.2.3.4)]: hello
+//	[CHAT] <Alice> hello
+var DefaultPatterns = []string{
+	`\[Chat::[A-Za-z]+\]\['(?P<name>[^']+)' \([^)]*\)\]: (?P<message>.*)$`,
+	`\[CHAT\] <(?P<name>[^>]+)> (?P<message>.*)$`,
+}
+
+type Message struct {
+	Name string
+	Text string
+}
+
+// Parser extracts in-game chat from container log lines. Each pattern must
+// have "name" and "message" capture groups.
+type Parser struct {
+	patterns []*regexp.Regexp
+}
+
+func NewParser(patterns []string) (*Parser, error) {
+	p := &Parser{}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chat pattern %q: %w", pattern, err)
+		}
+		if re.SubexpIndex("name") < 0 || re.SubexpIndex("message") < 0 {
+			return nil, fmt.Errorf("chat pattern %q needs (?P<name>...) and (?P<message>...) groups", pattern)
+		}
+		p.patterns = append(p.patterns, re)
+	}
+	if len(p.patterns) == 0 {
+		return nil, fmt.Errorf("no chat patterns")
+	}
+	return p, nil
+}
+
+// Parse returns the chat message on line, if any. Lines with an empty name
+// or message are ignored.
+func (p *Parser) Parse(line string) (Message, bool) {
+	line = strings.TrimRight(line, "\r\n")
+	for _, re := range p.patterns {
+		match := re.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		msg := Message{
+			Name: strings.TrimSpace(match[re.SubexpIndex("name")]),
+			Text: strings.TrimSpace(match[re.SubexpIndex("message")]),
+		}
+		if msg.Name == "" || msg.Text == "" {
+			return Message{}, false
+		}
+		return msg, true
+	}
+	return Message{}, false
+}
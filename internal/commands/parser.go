@@ -1,6 +1,12 @@
 package commands
 
-import "strings"
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type Type int
 
@@ -8,11 +14,29 @@ const (
 	Unknown Type = iota
 	StartPal
 	StopPal
+	CancelStop
+	Announce
+	Status
+	Players
+	RestartPal
+	Logs
+	Stats
+	Schedule
+	Backup
+	Backups
+	Restore
+	Say
+	Kick
+	Ban
+	Unban
+	Whitelist
+	Perf
 )
 
 type Command struct {
 	Type Type
 	Raw  string
+	Args []string
 }
 
 func Parse(body, prefix string) Command {
@@ -31,13 +55,98 @@ func Parse(body, prefix string) Command {
 	if len(fields) == 0 {
 		return Command{Type: Unknown, Raw: trimmed}
 	}
+	args := fields[1:]
 
 	switch strings.ToLower(fields[0]) {
 	case "startpal":
-		return Command{Type: StartPal, Raw: trimmed}
+		return Command{Type: StartPal, Raw: trimmed, Args: args}
 	case "stoppal":
-		return Command{Type: StopPal, Raw: trimmed}
+		return Command{Type: StopPal, Raw: trimmed, Args: args}
+	case "cancelstop":
+		return Command{Type: CancelStop, Raw: trimmed, Args: args}
+	case "announce":
+		return Command{Type: Announce, Raw: trimmed, Args: args}
+	case "status":
+		return Command{Type: Status, Raw: trimmed, Args: args}
+	case "players":
+		return Command{Type: Players, Raw: trimmed, Args: args}
+	case "restartpal":
+		return Command{Type: RestartPal, Raw: trimmed, Args: args}
+	case "logs":
+		return Command{Type: Logs, Raw: trimmed, Args: args}
+	case "stats":
+		return Command{Type: Stats, Raw: trimmed, Args: args}
+	case "schedule":
+		return Command{Type: Schedule, Raw: trimmed, Args: args}
+	case "backup":
+		return Command{Type: Backup, Raw: trimmed, Args: args}
+	case "backups":
+		return Command{Type: Backups, Raw: trimmed, Args: args}
+	case "restore":
+		return Command{Type: Restore, Raw: trimmed, Args: args}
+	case "say":
+		return Command{Type: Say, Raw: trimmed, Args: args}
+	case "kick":
+		return Command{Type: Kick, Raw: trimmed, Args: args}
+	case "ban":
+		return Command{Type: Ban, Raw: trimmed, Args: args}
+	case "unban":
+		return Command{Type: Unban, Raw: trimmed, Args: args}
+	case "whitelist":
+		return Command{Type: Whitelist, Raw: trimmed, Args: args}
+	case "perf":
+		return Command{Type: Perf, Raw: trimmed, Args: args}
 	default:
 		return Command{Type: Unknown, Raw: trimmed}
 	}
 }
+
+const MaxStopDelay = 2 * time.Hour
+
+// StopDelay parses the optional countdown of "!stoppal in 10m". No arguments
+// means an immediate stop and yields zero.
+func StopDelay(args []string) (time.Duration, error) {
+	if len(args) == 0 {
+		return 0, nil
+	}
+	if strings.EqualFold(args[0], "in") {
+		args = args[1:]
+	}
+	if len(args) != 1 {
+		return 0, errors.New("usage: stoppal [in <duration>], e.g. stoppal in 10m")
+	}
+	delay, err := time.ParseDuration(strings.ToLower(args[0]))
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", args[0])
+	}
+	if delay <= 0 {
+		return 0, errors.New("duration must be positive")
+	}
+	if delay > MaxStopDelay {
+		return 0, fmt.Errorf("duration must be at most %s", MaxStopDelay)
+	}
+	return delay, nil
+}
+
+const (
+	DefaultLogLines = 50
+	MaxLogLines     = 500
+)
+
+// LogLines parses the optional line count of "!logs [n]".
+func LogLines(args []string) (int, error) {
+	if len(args) == 0 {
+		return DefaultLogLines, nil
+	}
+	if len(args) != 1 {
+		return 0, errors.New("usage: logs [lines]")
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid line count %q", args[0])
+	}
+	if n > MaxLogLines {
+		n = MaxLogLines
+	}
+	return n, nil
+}
@@ -1,6 +1,10 @@
 package commands
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+	"time"
+)
 
 func TestParse(t *testing.T) {
 	tests := []struct {
@@ -11,6 +15,23 @@ func TestParse(t *testing.T) {
 	}{
 		{name: "start command", body: "!startpal", prefix: "!", want: StartPal},
 		{name: "stop command", body: "!stoppal", prefix: "!", want: StopPal},
+		{name: "cancel stop command", body: "!cancelstop", prefix: "!", want: CancelStop},
+		{name: "announce command", body: "!announce off", prefix: "!", want: Announce},
+		{name: "status command", body: "!status", prefix: "!", want: Status},
+		{name: "players command", body: "!players", prefix: "!", want: Players},
+		{name: "restart command", body: "!restartpal", prefix: "!", want: RestartPal},
+		{name: "logs command", body: "!logs 20", prefix: "!", want: Logs},
+		{name: "stats command", body: "!stats", prefix: "!", want: Stats},
+		{name: "schedule command", body: "!schedule add start 0 18 * * *", prefix: "!", want: Schedule},
+		{name: "backup command", body: "!backup", prefix: "!", want: Backup},
+		{name: "backups command", body: "!backups", prefix: "!", want: Backups},
+		{name: "restore command", body: "!restore 20240301-183000", prefix: "!", want: Restore},
+		{name: "say command", body: "!say hello everyone", prefix: "!", want: Say},
+		{name: "kick command", body: "!kick Alice", prefix: "!", want: Kick},
+		{name: "ban command", body: "!ban Alice griefing", prefix: "!", want: Ban},
+		{name: "unban command", body: "!unban steam_76561198000000001", prefix: "!", want: Unban},
+		{name: "whitelist command", body: "!whitelist add 76561198000000001", prefix: "!", want: Whitelist},
+		{name: "perf command", body: "!perf", prefix: "!", want: Perf},
 		{name: "with spaces", body: "   !startpal  ", prefix: "!", want: StartPal},
 		{name: "custom prefix", body: "$stoppal", prefix: "$", want: StopPal},
 		{name: "unknown", body: "!ping", prefix: "!", want: Unknown},
@@ -27,3 +48,72 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+func TestParseArgs(t *testing.T) {
+	got := Parse("!StopPal in 10m", "!")
+	if got.Type != StopPal {
+		t.Fatalf("Parse() type got %v want %v", got.Type, StopPal)
+	}
+	if !reflect.DeepEqual(got.Args, []string{"in", "10m"}) {
+		t.Fatalf("Parse() args got %q", got.Args)
+	}
+}
+
+func TestStopDelay(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "no args", args: nil, want: 0},
+		{name: "in minutes", args: []string{"in", "10m"}, want: 10 * time.Minute},
+		{name: "without in", args: []string{"90s"}, want: 90 * time.Second},
+		{name: "upper case", args: []string{"IN", "1H"}, want: time.Hour},
+		{name: "missing duration", args: []string{"in"}, wantErr: true},
+		{name: "garbage", args: []string{"in", "soon"}, wantErr: true},
+		{name: "negative", args: []string{"in", "-5m"}, wantErr: true},
+		{name: "too long", args: []string{"in", "3h"}, wantErr: true},
+		{name: "extra args", args: []string{"in", "5m", "please"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := StopDelay(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("StopDelay(%q) err %v wantErr %v", tt.args, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("StopDelay(%q) got %v want %v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    int
+		wantErr bool
+	}{
+		{name: "default", args: nil, want: DefaultLogLines},
+		{name: "explicit", args: []string{"20"}, want: 20},
+		{name: "capped", args: []string{"100000"}, want: MaxLogLines},
+		{name: "zero", args: []string{"0"}, wantErr: true},
+		{name: "not a number", args: []string{"all"}, wantErr: true},
+		{name: "too many args", args: []string{"1", "2"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LogLines(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LogLines(%q) err %v wantErr %v", tt.args, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("LogLines(%q) got %d want %d", tt.args, got, tt.want)
+			}
+		})
+	}
+}
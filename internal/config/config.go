@@ -3,10 +3,15 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
+
+	"pikabot/internal/chat"
+	"pikabot/internal/schedule"
 )
 
 type Config struct {
@@ -24,24 +29,124 @@ type Config struct {
 	RCONPort int
 	RCONPass string
 
+	// GameAPI selects how the bot talks to the game: "rcon" or "rest".
+	GameAPI     string
+	RESTAPIURL  string
+	RESTAPIUser string
+	RESTAPIPass string
+
 	CommandPrefix string
 	DataDir       string
+
+	IdleShutdownAfter time.Duration
+	IdlePollInterval  time.Duration
+
+	PresenceAnnounce     bool
+	PresencePollInterval time.Duration
+
+	ReadyTimeout time.Duration
+
+	// SaveFailureAbortsStop refuses to stop or restart the container when
+	// the RCON save before it fails, instead of only warning.
+	SaveFailureAbortsStop bool
+
+	AutoRestart          bool
+	AutoRestartBaseDelay time.Duration
+	AutoRestartMaxDelay  time.Duration
+	CrashLoopMax         int
+	CrashLoopWindow      time.Duration
+
+	MemoryWarnPercent int
+	StatsPollInterval time.Duration
+
+	RestartMaxUptime     time.Duration
+	RestartMemoryPercent int
+	RestartCountdown     time.Duration
+	RestartCheckInterval time.Duration
+
+	ScheduleStart  string
+	ScheduleStop   string
+	ScheduleBackup string
+
+	BackupDir         string
+	BackupSourceDir   string
+	SaveContainerPath string
+	BackupKeepLast    int
+	BackupKeepDaily   int
+	BackupKeepWeekly  int
+
+	ChatBridge     bool
+	ChatBridgeRate int
+	// ChatLogPattern overrides chat.DefaultPatterns when set.
+	ChatLogPattern string
+
+	WhitelistEnforce      bool
+	WhitelistPollInterval time.Duration
+
+	FPSWarnThreshold int
+	FPSWarnAfter     time.Duration
+	PerfPollInterval time.Duration
 }
 
 func Load() (Config, error) {
 	cfg := Config{
-		MatrixHomeserver:    strings.TrimSpace(os.Getenv("MATRIX_HOMESERVER")),
-		MatrixAccessToken:   strings.TrimSpace(os.Getenv("MATRIX_ACCESS_TOKEN")),
-		MatrixUser:          strings.TrimSpace(os.Getenv("MATRIX_USER")),
-		MatrixPassword:      strings.TrimSpace(os.Getenv("MATRIX_PASSWORD")),
-		MatrixUserID:        strings.TrimSpace(os.Getenv("MATRIX_USER_ID")),
-		MatrixRoomID:        strings.TrimSpace(os.Getenv("MATRIX_ROOM_ID")),
-		DockerContainerName: envOrDefault("DOCKER_CONTAINER_NAME", "Palworld"),
-		RCONHost:            envOrDefault("RCON_HOST", "127.0.0.1"),
-		RCONPort:            intEnvOrDefault("RCON_PORT", 25575),
-		RCONPass:            strings.TrimSpace(os.Getenv("RCON_PASS")),
-		CommandPrefix:       envOrDefault("COMMAND_PREFIX", "!"),
-		DataDir:             envOrDefault("DATA_DIR", "./data"),
+		MatrixHomeserver:      strings.TrimSpace(os.Getenv("MATRIX_HOMESERVER")),
+		MatrixAccessToken:     strings.TrimSpace(os.Getenv("MATRIX_ACCESS_TOKEN")),
+		MatrixUser:            strings.TrimSpace(os.Getenv("MATRIX_USER")),
+		MatrixPassword:        strings.TrimSpace(os.Getenv("MATRIX_PASSWORD")),
+		MatrixUserID:          strings.TrimSpace(os.Getenv("MATRIX_USER_ID")),
+		MatrixRoomID:          strings.TrimSpace(os.Getenv("MATRIX_ROOM_ID")),
+		DockerContainerName:   envOrDefault("DOCKER_CONTAINER_NAME", "Palworld"),
+		RCONHost:              envOrDefault("RCON_HOST", "127.0.0.1"),
+		RCONPort:              intEnvOrDefault("RCON_PORT", 25575),
+		RCONPass:              strings.TrimSpace(os.Getenv("RCON_PASS")),
+		GameAPI:               strings.ToLower(envOrDefault("GAME_API", "rcon")),
+		RESTAPIURL:            envOrDefault("REST_API_URL", "http://127.0.0.1:8212"),
+		RESTAPIUser:           envOrDefault("REST_API_USER", "admin"),
+		RESTAPIPass:           strings.TrimSpace(os.Getenv("REST_API_PASS")),
+		CommandPrefix:         envOrDefault("COMMAND_PREFIX", "!"),
+		DataDir:               envOrDefault("DATA_DIR", "./data"),
+		IdleShutdownAfter:     durationEnvOrDefault("IDLE_SHUTDOWN_AFTER", 0),
+		IdlePollInterval:      durationEnvOrDefault("IDLE_POLL_INTERVAL", time.Minute),
+		PresenceAnnounce:      boolEnvOrDefault("PRESENCE_ANNOUNCE", true),
+		PresencePollInterval:  durationEnvOrDefault("PRESENCE_POLL_INTERVAL", 30*time.Second),
+		ReadyTimeout:          durationEnvOrDefault("READY_TIMEOUT", 5*time.Minute),
+		SaveFailureAbortsStop: boolEnvOrDefault("SAVE_FAILURE_ABORTS_STOP", true),
+		AutoRestart:           boolEnvOrDefault("AUTO_RESTART", false),
+		AutoRestartBaseDelay:  durationEnvOrDefault("AUTO_RESTART_BASE_DELAY", 10*time.Second),
+		AutoRestartMaxDelay:   durationEnvOrDefault("AUTO_RESTART_MAX_DELAY", 5*time.Minute),
+		CrashLoopMax:          intEnvOrDefault("CRASH_LOOP_MAX", 3),
+		CrashLoopWindow:       durationEnvOrDefault("CRASH_LOOP_WINDOW", 30*time.Minute),
+		MemoryWarnPercent:     intEnvOrDefault("MEMORY_WARN_PERCENT", 0),
+		StatsPollInterval:     durationEnvOrDefault("STATS_POLL_INTERVAL", time.Minute),
+		RestartMaxUptime:      durationEnvOrDefault("RESTART_MAX_UPTIME", 0),
+		RestartMemoryPercent:  intEnvOrDefault("RESTART_MEMORY_PERCENT", 0),
+		RestartCountdown:      durationEnvOrDefault("RESTART_COUNTDOWN", 10*time.Minute),
+		RestartCheckInterval:  durationEnvOrDefault("RESTART_CHECK_INTERVAL", time.Minute),
+		ScheduleStart:         strings.TrimSpace(os.Getenv("SCHEDULE_START")),
+		ScheduleStop:          strings.TrimSpace(os.Getenv("SCHEDULE_STOP")),
+		ScheduleBackup:        strings.TrimSpace(os.Getenv("SCHEDULE_BACKUP")),
+		BackupDir:             strings.TrimSpace(os.Getenv("BACKUP_DIR")),
+		BackupSourceDir:       strings.TrimSpace(os.Getenv("BACKUP_SOURCE_DIR")),
+		SaveContainerPath:     envOrDefault("SAVE_CONTAINER_PATH", "/palworld/Pal/Saved"),
+		BackupKeepLast:        intEnvOrDefault("BACKUP_KEEP_LAST", 10),
+		BackupKeepDaily:       intEnvOrDefault("BACKUP_KEEP_DAILY", 7),
+		BackupKeepWeekly:      intEnvOrDefault("BACKUP_KEEP_WEEKLY", 4),
+		ChatBridge:            boolEnvOrDefault("CHAT_BRIDGE", false),
+		ChatBridgeRate:        intEnvOrDefault("CHAT_BRIDGE_RATE", 20),
+		ChatLogPattern:        strings.TrimSpace(os.Getenv("CHAT_LOG_PATTERN")),
+		WhitelistEnforce:      boolEnvOrDefault("WHITELIST_ENFORCE", false),
+		WhitelistPollInterval: durationEnvOrDefault("WHITELIST_POLL_INTERVAL", 15*time.Second),
+		FPSWarnThreshold:      intEnvOrDefault("FPS_WARN_THRESHOLD", 0),
+		FPSWarnAfter:          durationEnvOrDefault("FPS_WARN_AFTER", 5*time.Minute),
+		PerfPollInterval:      durationEnvOrDefault("PERF_POLL_INTERVAL", 30*time.Second),
+	}
+	if cfg.RESTAPIPass == "" {
+		// Both APIs authenticate with the server's AdminPassword.
+		cfg.RESTAPIPass = cfg.RCONPass
+	}
+	if cfg.BackupDir == "" {
+		cfg.BackupDir = filepath.Join(cfg.DataDir, "backups")
 	}
 
 	cfg.AllowedMXIDs = parseAllowlist(os.Getenv("ALLOWED_MXIDS"))
@@ -61,6 +166,46 @@ func (c Config) AccessTokenPath() string {
 	return filepath.Join(c.DataDir, "matrix_access.token")
 }
 
+func (c Config) SettingsPath() string {
+	return filepath.Join(c.DataDir, "settings.json")
+}
+
+func (c Config) AuditLogPath() string {
+	return filepath.Join(c.DataDir, "audit.log")
+}
+
+func (c Config) WhitelistPath() string {
+	return filepath.Join(c.DataDir, "whitelist.json")
+}
+
+func (c Config) SchedulesPath() string {
+	return filepath.Join(c.DataDir, "schedules.json")
+}
+
+// ChatPatterns returns the log patterns the chat bridge uses to recognise
+// in-game chat.
+func (c Config) ChatPatterns() []string {
+	if c.ChatLogPattern != "" {
+		return []string{c.ChatLogPattern}
+	}
+	return chat.DefaultPatterns
+}
+
+// Schedules returns the start/stop schedules defined in the environment.
+func (c Config) Schedules() []schedule.Entry {
+	var entries []schedule.Entry
+	if c.ScheduleStart != "" {
+		entries = append(entries, schedule.Entry{ID: "config-start", Action: schedule.Start, Spec: c.ScheduleStart})
+	}
+	if c.ScheduleStop != "" {
+		entries = append(entries, schedule.Entry{ID: "config-stop", Action: schedule.Stop, Spec: c.ScheduleStop})
+	}
+	if c.ScheduleBackup != "" {
+		entries = append(entries, schedule.Entry{ID: "config-backup", Action: schedule.Backup, Spec: c.ScheduleBackup})
+	}
+	return entries
+}
+
 func (c Config) validate() error {
 	if c.MatrixHomeserver == "" {
 		return errors.New("MATRIX_HOMESERVER is required")
@@ -76,15 +221,107 @@ func (c Config) validate() error {
 			return errors.New("set MATRIX_ACCESS_TOKEN or both MATRIX_USER and MATRIX_PASSWORD")
 		}
 	}
-	if c.RCONPass == "" {
-		return errors.New("RCON_PASS is required")
-	}
-	if c.RCONPort <= 0 {
-		return fmt.Errorf("invalid RCON_PORT: %d", c.RCONPort)
+	switch c.GameAPI {
+	case "rcon":
+		if c.RCONPass == "" {
+			return errors.New("RCON_PASS is required")
+		}
+		if c.RCONPort <= 0 {
+			return fmt.Errorf("invalid RCON_PORT: %d", c.RCONPort)
+		}
+	case "rest":
+		if c.RESTAPIPass == "" {
+			return errors.New("REST_API_PASS (or RCON_PASS) is required with GAME_API=rest")
+		}
+		if u, err := url.Parse(c.RESTAPIURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid REST_API_URL: %q", c.RESTAPIURL)
+		}
+	default:
+		return fmt.Errorf("invalid GAME_API: %q (use rcon or rest)", c.GameAPI)
 	}
 	if strings.TrimSpace(c.CommandPrefix) == "" {
 		return errors.New("COMMAND_PREFIX must not be empty")
 	}
+	if c.IdleShutdownAfter < 0 {
+		return fmt.Errorf("invalid IDLE_SHUTDOWN_AFTER: %s", c.IdleShutdownAfter)
+	}
+	if c.IdlePollInterval < time.Second {
+		return fmt.Errorf("invalid IDLE_POLL_INTERVAL: %s (minimum 1s)", c.IdlePollInterval)
+	}
+	if c.PresencePollInterval < time.Second {
+		return fmt.Errorf("invalid PRESENCE_POLL_INTERVAL: %s (minimum 1s)", c.PresencePollInterval)
+	}
+	if c.ReadyTimeout < 10*time.Second {
+		return fmt.Errorf("invalid READY_TIMEOUT: %s (minimum 10s)", c.ReadyTimeout)
+	}
+	if c.MemoryWarnPercent < 0 || c.MemoryWarnPercent > 100 {
+		return fmt.Errorf("invalid MEMORY_WARN_PERCENT: %d", c.MemoryWarnPercent)
+	}
+	if c.StatsPollInterval < 5*time.Second {
+		return fmt.Errorf("invalid STATS_POLL_INTERVAL: %s (minimum 5s)", c.StatsPollInterval)
+	}
+	if c.RestartMaxUptime < 0 {
+		return fmt.Errorf("invalid RESTART_MAX_UPTIME: %s", c.RestartMaxUptime)
+	}
+	if c.RestartMemoryPercent < 0 || c.RestartMemoryPercent > 100 {
+		return fmt.Errorf("invalid RESTART_MEMORY_PERCENT: %d", c.RestartMemoryPercent)
+	}
+	if c.RestartCountdown < 0 || c.RestartCountdown > 2*time.Hour {
+		return fmt.Errorf("invalid RESTART_COUNTDOWN: %s (0 to 2h)", c.RestartCountdown)
+	}
+	if c.RestartCheckInterval < 5*time.Second {
+		return fmt.Errorf("invalid RESTART_CHECK_INTERVAL: %s (minimum 5s)", c.RestartCheckInterval)
+	}
+	if c.ScheduleStart != "" {
+		if _, err := schedule.ParseCron(c.ScheduleStart); err != nil {
+			return fmt.Errorf("invalid SCHEDULE_START: %w", err)
+		}
+	}
+	if c.ScheduleStop != "" {
+		if _, err := schedule.ParseCron(c.ScheduleStop); err != nil {
+			return fmt.Errorf("invalid SCHEDULE_STOP: %w", err)
+		}
+	}
+	if c.ScheduleBackup != "" {
+		if _, err := schedule.ParseCron(c.ScheduleBackup); err != nil {
+			return fmt.Errorf("invalid SCHEDULE_BACKUP: %w", err)
+		}
+	}
+	if c.BackupKeepLast < 1 {
+		return fmt.Errorf("invalid BACKUP_KEEP_LAST: %d (minimum 1)", c.BackupKeepLast)
+	}
+	if c.BackupKeepDaily < 0 || c.BackupKeepWeekly < 0 {
+		return errors.New("BACKUP_KEEP_DAILY and BACKUP_KEEP_WEEKLY must not be negative")
+	}
+	if c.FPSWarnThreshold < 0 || c.FPSWarnThreshold > 120 {
+		return fmt.Errorf("invalid FPS_WARN_THRESHOLD: %d", c.FPSWarnThreshold)
+	}
+	if c.FPSWarnAfter < time.Minute {
+		return fmt.Errorf("invalid FPS_WARN_AFTER: %s (minimum 1m)", c.FPSWarnAfter)
+	}
+	if c.PerfPollInterval < 5*time.Second {
+		return fmt.Errorf("invalid PERF_POLL_INTERVAL: %s (minimum 5s)", c.PerfPollInterval)
+	}
+	if c.WhitelistPollInterval < 5*time.Second {
+		return fmt.Errorf("invalid WHITELIST_POLL_INTERVAL: %s (minimum 5s)", c.WhitelistPollInterval)
+	}
+	if c.ChatBridgeRate < 1 {
+		return fmt.Errorf("invalid CHAT_BRIDGE_RATE: %d (minimum 1)", c.ChatBridgeRate)
+	}
+	if _, err := chat.NewParser(c.ChatPatterns()); err != nil {
+		return fmt.Errorf("invalid CHAT_LOG_PATTERN: %w", err)
+	}
+	if c.AutoRestart {
+		if c.AutoRestartBaseDelay <= 0 || c.AutoRestartMaxDelay < c.AutoRestartBaseDelay {
+			return errors.New("AUTO_RESTART_MAX_DELAY must be at least AUTO_RESTART_BASE_DELAY")
+		}
+		if c.CrashLoopMax < 1 {
+			return fmt.Errorf("invalid CRASH_LOOP_MAX: %d", c.CrashLoopMax)
+		}
+		if c.CrashLoopWindow <= 0 {
+			return fmt.Errorf("invalid CRASH_LOOP_WINDOW: %s", c.CrashLoopWindow)
+		}
+	}
 	return nil
 }
 
@@ -119,3 +356,27 @@ func intEnvOrDefault(key string, fallback int) int {
 	}
 	return n
 }
+
+func durationEnvOrDefault(key string, fallback time.Duration) time.Duration {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fallback
+	}
+	return d
+}
+
+func boolEnvOrDefault(key string, fallback bool) bool {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return fallback
+	}
+	return b
+}
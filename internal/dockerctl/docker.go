@@ -5,9 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/errdefs"
 )
@@ -15,12 +17,20 @@ import (
 type Controller struct {
 	cli           *client.Client
 	containerName string
+
+	mu       sync.Mutex
+	expected map[events.Action]time.Time
 }
 
 type Status struct {
-	Exists  bool
-	Running bool
-	State   string
+	Exists    bool
+	Running   bool
+	State     string
+	StartedAt time.Time
+	// Health is empty when the image defines no HEALTHCHECK.
+	Health    string
+	ExitCode  int
+	OOMKilled bool
 }
 
 func New(containerName string) (*Controller, error) {
@@ -31,7 +41,7 @@ func New(containerName string) (*Controller, error) {
 	if err != nil {
 		return nil, fmt.Errorf("create docker client: %w", err)
 	}
-	return &Controller{cli: cli, containerName: containerName}, nil
+	return &Controller{cli: cli, containerName: containerName, expected: make(map[events.Action]time.Time)}, nil
 }
 
 func (c *Controller) Close() error {
@@ -47,14 +57,19 @@ func (c *Controller) Status(ctx context.Context) (Status, error) {
 		return Status{}, fmt.Errorf("inspect container %q: %w", c.containerName, err)
 	}
 
-	state := ""
-	running := false
+	status := Status{Exists: true}
 	if inspect.ContainerJSONBase != nil && inspect.ContainerJSONBase.State != nil {
-		state = inspect.ContainerJSONBase.State.Status
-		running = inspect.ContainerJSONBase.State.Running
+		status.State = inspect.ContainerJSONBase.State.Status
+		status.Running = inspect.ContainerJSONBase.State.Running
+		status.StartedAt = parseDockerTime(inspect.ContainerJSONBase.State.StartedAt)
+		status.ExitCode = inspect.ContainerJSONBase.State.ExitCode
+		status.OOMKilled = inspect.ContainerJSONBase.State.OOMKilled
+		if health := inspect.ContainerJSONBase.State.Health; health != nil {
+			status.Health = health.Status
+		}
 	}
 
-	return Status{Exists: true, Running: running, State: state}, nil
+	return status, nil
 }
 
 func (c *Controller) Start(ctx context.Context) error {
@@ -69,8 +84,33 @@ func (c *Controller) Stop(ctx context.Context, timeout time.Duration) error {
 	if seconds < 1 {
 		seconds = 10
 	}
+	c.expect(time.Duration(seconds)*time.Second, events.ActionDie)
 	if err := c.cli.ContainerStop(ctx, c.containerName, container.StopOptions{Timeout: &seconds}); err != nil {
+		c.forget(events.ActionDie)
 		return fmt.Errorf("stop container %q: %w", c.containerName, err)
 	}
 	return nil
 }
+
+func (c *Controller) Restart(ctx context.Context, timeout time.Duration) error {
+	seconds := int(timeout.Seconds())
+	if seconds < 1 {
+		seconds = 10
+	}
+	c.expect(time.Duration(seconds)*time.Second, events.ActionDie, events.ActionRestart)
+	if err := c.cli.ContainerRestart(ctx, c.containerName, container.StopOptions{Timeout: &seconds}); err != nil {
+		c.forget(events.ActionDie, events.ActionRestart)
+		return fmt.Errorf("restart container %q: %w", c.containerName, err)
+	}
+	return nil
+}
+
+// parseDockerTime returns the zero time for the "0001-01-01T00:00:00Z"
+// placeholder Docker reports for containers that never ran.
+func parseDockerTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil || t.Year() <= 1 {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,105 @@
+package dockerctl
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+)
+
+type Event struct {
+	Action    events.Action
+	Time      time.Time
+	ExitCode  int
+	OOMKilled bool
+	// Expected is set on die and restart events caused by this controller's
+	// own Stop or Restart calls.
+	Expected bool
+}
+
+// Events streams start, die, oom and restart events for the configured
+// container. The error channel receives one value when the stream ends;
+// callers are expected to resubscribe.
+func (c *Controller) Events(ctx context.Context) (<-chan Event, <-chan error) {
+	args := filters.NewArgs(
+		filters.Arg("type", string(events.ContainerEventType)),
+		filters.Arg("container", c.containerName),
+		filters.Arg("event", string(events.ActionStart)),
+		filters.Arg("event", string(events.ActionDie)),
+		filters.Arg("event", string(events.ActionOOM)),
+		filters.Arg("event", string(events.ActionRestart)),
+	)
+	messages, errs := c.cli.Events(ctx, events.ListOptions{Filters: args})
+
+	out := make(chan Event)
+	outErrs := make(chan error, 1)
+	go func() {
+		defer close(out)
+		defer close(outErrs)
+		oomKilled := false
+		for {
+			select {
+			case msg := <-messages:
+				evt := Event{Action: msg.Action, Time: time.Unix(0, msg.TimeNano)}
+				switch msg.Action {
+				case events.ActionOOM:
+					// Docker reports oom before the matching die event.
+					oomKilled = true
+				case events.ActionDie:
+					evt.ExitCode, _ = strconv.Atoi(msg.Actor.Attributes["exitCode"])
+					evt.OOMKilled = oomKilled
+					evt.Expected = c.consumeExpected(events.ActionDie, evt.Time)
+					oomKilled = false
+				case events.ActionRestart:
+					evt.Expected = c.consumeExpected(events.ActionRestart, evt.Time)
+				case events.ActionStart:
+					oomKilled = false
+				}
+				select {
+				case out <- evt:
+				case <-ctx.Done():
+					outErrs <- ctx.Err()
+					return
+				}
+			case err := <-errs:
+				outErrs <- err
+				return
+			}
+		}
+	}()
+	return out, outErrs
+}
+
+// expect marks the next event of each action within the stop timeout plus
+// some slack as caused by us.
+func (c *Controller) expect(timeout time.Duration, actions ...events.Action) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	until := time.Now().Add(timeout + 30*time.Second)
+	for _, action := range actions {
+		c.expected[action] = until
+	}
+}
+
+// forget drops markers set by expect when the call they were armed for
+// failed, so a real crash in that window is still alerted.
+func (c *Controller) forget(actions ...events.Action) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, action := range actions {
+		delete(c.expected, action)
+	}
+}
+
+func (c *Controller) consumeExpected(action events.Action, at time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	until, ok := c.expected[action]
+	if !ok {
+		return false
+	}
+	delete(c.expected, action)
+	return !at.After(until)
+}
@@ -0,0 +1,31 @@
+package dockerctl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+)
+
+func TestExpectedEvents(t *testing.T) {
+	c := &Controller{expected: make(map[events.Action]time.Time)}
+	now := time.Now()
+
+	c.expect(10*time.Second, events.ActionDie, events.ActionRestart)
+	if !c.consumeExpected(events.ActionDie, now) {
+		t.Fatal("die within the window should be expected")
+	}
+	if c.consumeExpected(events.ActionDie, now) {
+		t.Fatal("a marker is consumed by the first matching event")
+	}
+	if c.consumeExpected(events.ActionRestart, now.Add(time.Hour)) {
+		t.Fatal("restart after the window should not be expected")
+	}
+
+	// A failed Stop must not hide a crash that happens right after it.
+	c.expect(10*time.Second, events.ActionDie)
+	c.forget(events.ActionDie)
+	if c.consumeExpected(events.ActionDie, now) {
+		t.Fatal("die after forget should not be expected")
+	}
+}
@@ -0,0 +1,69 @@
+package dockerctl
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// FollowLogs streams container output line by line from since onwards, with
+// stdout and stderr merged and ANSI escapes removed. The stream ends when
+// the container stops; the error channel then receives one value and
+// callers are expected to resubscribe.
+func (c *Controller) FollowLogs(ctx context.Context, since time.Time) (<-chan string, <-chan error) {
+	out := make(chan string)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(out)
+		defer close(errs)
+		errs <- c.followLogs(ctx, since, out)
+	}()
+	return out, errs
+}
+
+func (c *Controller) followLogs(ctx context.Context, since time.Time, out chan<- string) error {
+	inspect, err := c.cli.ContainerInspect(ctx, c.containerName)
+	if err != nil {
+		return fmt.Errorf("inspect container %q: %w", c.containerName, err)
+	}
+	rc, err := c.cli.ContainerLogs(ctx, c.containerName, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+		Since:      fmt.Sprintf("%d.%09d", since.Unix(), since.Nanosecond()),
+	})
+	if err != nil {
+		return fmt.Errorf("follow logs of container %q: %w", c.containerName, err)
+	}
+	defer rc.Close()
+
+	var stream io.Reader = rc
+	if inspect.Config == nil || !inspect.Config.Tty {
+		pr, pw := io.Pipe()
+		go func() {
+			_, err := stdcopy.StdCopy(pw, pw, rc)
+			pw.CloseWithError(err)
+		}()
+		defer pr.Close()
+		stream = pr
+	}
+
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		select {
+		case out <- StripANSI(scanner.Text()):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("follow logs of container %q: %w", c.containerName, err)
+	}
+	return nil
+}
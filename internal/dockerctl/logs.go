@@ -0,0 +1,144 @@
+package dockerctl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+const Redacted = "[REDACTED]"
+
+var (
+	ansiPattern   = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)|\x1b[@-Z\\-_]`)
+	secretEnvKeys = []string{"PASSWORD", "PASS", "SECRET", "TOKEN", "APIKEY", "API_KEY"}
+)
+
+// minSecretLength keeps tiny values such as "1" or "no" from redacting half
+// the output.
+const minSecretLength = 4
+
+// maxLogBytes bounds how much of the tailed log is kept in memory, in case
+// a few of the requested lines are huge.
+const maxLogBytes = 4 << 20
+
+// Logs returns the last lines of container output with stdout and stderr
+// merged in order, ANSI escapes removed and the values of secret-looking
+// container environment variables redacted.
+func (c *Controller) Logs(ctx context.Context, lines int) (string, error) {
+	inspect, err := c.cli.ContainerInspect(ctx, c.containerName)
+	if err != nil {
+		return "", fmt.Errorf("inspect container %q: %w", c.containerName, err)
+	}
+
+	rc, err := c.cli.ContainerLogs(ctx, c.containerName, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Tail:       strconv.Itoa(lines),
+	})
+	if err != nil {
+		return "", fmt.Errorf("read logs of container %q: %w", c.containerName, err)
+	}
+	defer rc.Close()
+
+	tty := inspect.Config != nil && inspect.Config.Tty
+	text, err := readLogs(rc, tty, maxLogBytes)
+	if err != nil {
+		return "", fmt.Errorf("read logs of container %q: %w", c.containerName, err)
+	}
+
+	var env []string
+	if inspect.Config != nil {
+		env = inspect.Config.Env
+	}
+	return Redact(StripANSI(text), SecretEnvValues(env)), nil
+}
+
+// readLogs reads a log stream, demultiplexing stdout and stderr unless the
+// container has a TTY, and keeps only its last limit bytes: the tail is
+// what was asked for. Output cut off at the front is marked as truncated.
+func readLogs(r io.Reader, tty bool, limit int) (string, error) {
+	out := &tailBuffer{limit: limit}
+	var err error
+	if tty {
+		_, err = io.Copy(out, r)
+	} else {
+		_, err = stdcopy.StdCopy(out, out, r)
+	}
+	if err != nil {
+		return "", err
+	}
+	if !out.truncated {
+		return string(out.buf), nil
+	}
+	text := out.buf
+	// Drop the partial first line, which may also be a split rune.
+	if i := bytes.IndexByte(text, '\n'); i >= 0 {
+		text = text[i+1:]
+	}
+	return "[truncated]\n" + string(text), nil
+}
+
+// tailBuffer is an io.Writer that keeps the last limit bytes written to it.
+type tailBuffer struct {
+	limit     int
+	buf       []byte
+	truncated bool
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.buf = append(t.buf, p...)
+	if over := len(t.buf) - t.limit; over > 0 {
+		t.buf = t.buf[:copy(t.buf, t.buf[over:])]
+		t.truncated = true
+	}
+	return len(p), nil
+}
+
+func StripANSI(text string) string {
+	text = ansiPattern.ReplaceAllString(text, "")
+	return strings.ReplaceAll(text, "\r", "")
+}
+
+// Redact replaces every occurrence of the given secrets. Longer secrets go
+// first so a secret containing another one is not left half visible.
+func Redact(text string, secrets []string) string {
+	sorted := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		if len(secret) >= minSecretLength {
+			sorted = append(sorted, secret)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+	for _, secret := range sorted {
+		text = strings.ReplaceAll(text, secret, Redacted)
+	}
+	return text
+}
+
+// SecretEnvValues picks the values of KEY=VALUE entries whose key looks like
+// it holds a credential, e.g. ADMIN_PASSWORD or RCON_PASS.
+func SecretEnvValues(env []string) []string {
+	var secrets []string
+	for _, entry := range env {
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok || value == "" {
+			continue
+		}
+		key = strings.ToUpper(key)
+		for _, marker := range secretEnvKeys {
+			if strings.Contains(key, marker) {
+				secrets = append(secrets, value)
+				break
+			}
+		}
+	}
+	return secrets
+}
@@ -0,0 +1,105 @@
+package dockerctl
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+func TestStripANSI(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "plain", in: "hello\n", want: "hello\n"},
+		{name: "colors", in: "\x1b[32mINFO\x1b[0m started\n", want: "INFO started\n"},
+		{name: "cursor and erase", in: "\x1b[2K\x1b[1Aprogress 50%\r\n", want: "progress 50%\n"},
+		{name: "osc title", in: "\x1b]0;palworld\x07ready", want: "ready"},
+		{name: "private mode", in: "\x1b[?25lhidden cursor\x1b[?25h", want: "hidden cursor"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripANSI(tt.in); got != tt.want {
+				t.Fatalf("StripANSI(%q) got %q want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		secrets []string
+		want    string
+	}{
+		{
+			name:    "single secret",
+			text:    "AdminPassword=hunter22 set",
+			secrets: []string{"hunter22"},
+			want:    "AdminPassword=[REDACTED] set",
+		},
+		{
+			name:    "overlapping secrets longest first",
+			text:    "pass hunter22x and hunter22",
+			secrets: []string{"hunter22", "hunter22x"},
+			want:    "pass [REDACTED] and [REDACTED]",
+		},
+		{
+			name:    "short values ignored",
+			text:    "players 1 of 32",
+			secrets: []string{"1", ""},
+			want:    "players 1 of 32",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Redact(tt.text, tt.secrets); got != tt.want {
+				t.Fatalf("Redact() got %q want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSecretEnvValues(t *testing.T) {
+	env := []string{
+		"PLAYERS=16",
+		"ADMIN_PASSWORD=hunter22",
+		"SERVER_PASSWORD=",
+		"RCON_PASS=rconpass",
+		"DISCORD_TOKEN=abc.def",
+		"PATH=/usr/bin",
+		"MALFORMED",
+	}
+	want := []string{"hunter22", "rconpass", "abc.def"}
+	if got := SecretEnvValues(env); !reflect.DeepEqual(got, want) {
+		t.Fatalf("SecretEnvValues() got %q want %q", got, want)
+	}
+}
+
+func TestReadLogs(t *testing.T) {
+	var muxed bytes.Buffer
+	_, _ = stdcopy.NewStdWriter(&muxed, stdcopy.Stdout).Write([]byte("started\n"))
+	_, _ = stdcopy.NewStdWriter(&muxed, stdcopy.Stderr).Write([]byte("warning\n"))
+
+	got, err := readLogs(bytes.NewReader(muxed.Bytes()), false, 1024)
+	if err != nil || got != "started\nwarning\n" {
+		t.Fatalf("readLogs() multiplexed got %q, %v", got, err)
+	}
+
+	got, err = readLogs(strings.NewReader("old line\nnew line 1\nnew line 2\n"), true, 24)
+	if err != nil || got != "[truncated]\nnew line 1\nnew line 2\n" {
+		t.Fatalf("readLogs() over the limit got %q, %v", got, err)
+	}
+
+	got, err = readLogs(strings.NewReader("0123456789"), true, 10)
+	if err != nil || got != "0123456789" {
+		t.Fatalf("readLogs() at the limit got %q, %v", got, err)
+	}
+}
@@ -0,0 +1,36 @@
+package dockerctl
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// HostPath maps a path inside the container to the host path of the bind
+// mount or volume that backs it, picking the most specific mount.
+func (c *Controller) HostPath(ctx context.Context, containerPath string) (string, error) {
+	inspect, err := c.cli.ContainerInspect(ctx, c.containerName)
+	if err != nil {
+		return "", fmt.Errorf("inspect container %q: %w", c.containerName, err)
+	}
+
+	containerPath = path.Clean(containerPath)
+	best := ""
+	hostPath := ""
+	for _, m := range inspect.Mounts {
+		dest := path.Clean(m.Destination)
+		if m.Source == "" || len(dest) <= len(best) {
+			continue
+		}
+		if containerPath != dest && !strings.HasPrefix(containerPath, strings.TrimSuffix(dest, "/")+"/") {
+			continue
+		}
+		best = dest
+		hostPath = path.Join(m.Source, strings.TrimPrefix(containerPath, dest))
+	}
+	if hostPath == "" {
+		return "", fmt.Errorf("no mount of container %q covers %s", c.containerName, containerPath)
+	}
+	return hostPath, nil
+}
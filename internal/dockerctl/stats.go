@@ -0,0 +1,83 @@
+package dockerctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+type Stats struct {
+	CPUPercent    float64
+	MemoryUsage   uint64
+	MemoryLimit   uint64
+	MemoryPercent float64
+	NetworkRx     uint64
+	NetworkTx     uint64
+	BlockRead     uint64
+	BlockWrite    uint64
+}
+
+// Stats takes one sample of resource usage. Docker collects two readings a
+// second apart for non-streaming requests, which the CPU percentage needs.
+func (c *Controller) Stats(ctx context.Context) (Stats, error) {
+	resp, err := c.cli.ContainerStats(ctx, c.containerName, false)
+	if err != nil {
+		return Stats{}, fmt.Errorf("read stats of container %q: %w", c.containerName, err)
+	}
+	defer resp.Body.Close()
+
+	var raw container.StatsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return Stats{}, fmt.Errorf("decode stats of container %q: %w", c.containerName, err)
+	}
+	return computeStats(raw), nil
+}
+
+// computeStats follows the docker CLI: page cache is not counted as used
+// memory, and CPU usage is relative to one core, so a busy 4-core container
+// can show up to 400%.
+func computeStats(raw container.StatsResponse) Stats {
+	stats := Stats{
+		MemoryUsage: raw.MemoryStats.Usage,
+		MemoryLimit: raw.MemoryStats.Limit,
+	}
+
+	cache := raw.MemoryStats.Stats["inactive_file"]
+	if v, ok := raw.MemoryStats.Stats["total_inactive_file"]; ok {
+		cache = v
+	}
+	if cache < stats.MemoryUsage {
+		stats.MemoryUsage -= cache
+	}
+	if stats.MemoryLimit > 0 {
+		stats.MemoryPercent = float64(stats.MemoryUsage) / float64(stats.MemoryLimit) * 100
+	}
+
+	cpuDelta := float64(raw.CPUStats.CPUUsage.TotalUsage) - float64(raw.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(raw.CPUStats.SystemUsage) - float64(raw.PreCPUStats.SystemUsage)
+	cpus := float64(raw.CPUStats.OnlineCPUs)
+	if cpus == 0 {
+		cpus = float64(len(raw.CPUStats.CPUUsage.PercpuUsage))
+	}
+	// Without a previous reading the deltas are cumulative since boot.
+	if raw.PreCPUStats.SystemUsage > 0 && cpuDelta > 0 && systemDelta > 0 {
+		stats.CPUPercent = cpuDelta / systemDelta * cpus * 100
+	}
+
+	for _, network := range raw.Networks {
+		stats.NetworkRx += network.RxBytes
+		stats.NetworkTx += network.TxBytes
+	}
+	for _, entry := range raw.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			stats.BlockRead += entry.Value
+		case "write":
+			stats.BlockWrite += entry.Value
+		}
+	}
+	return stats
+}
@@ -0,0 +1,69 @@
+package dockerctl
+
+import (
+	"math"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+func TestComputeStats(t *testing.T) {
+	raw := container.StatsResponse{
+		CPUStats: container.CPUStats{
+			CPUUsage:    container.CPUUsage{TotalUsage: 3_000_000_000},
+			SystemUsage: 20_000_000_000,
+			OnlineCPUs:  4,
+		},
+		PreCPUStats: container.CPUStats{
+			CPUUsage:    container.CPUUsage{TotalUsage: 2_000_000_000},
+			SystemUsage: 16_000_000_000,
+		},
+		MemoryStats: container.MemoryStats{
+			Usage: 9 << 30,
+			Limit: 16 << 30,
+			Stats: map[string]uint64{"inactive_file": 1 << 30},
+		},
+		Networks: map[string]container.NetworkStats{
+			"eth0": {RxBytes: 100, TxBytes: 200},
+			"eth1": {RxBytes: 10, TxBytes: 20},
+		},
+		BlkioStats: container.BlkioStats{
+			IoServiceBytesRecursive: []container.BlkioStatEntry{
+				{Op: "Read", Value: 1000},
+				{Op: "Write", Value: 500},
+				{Op: "read", Value: 24},
+				{Op: "Total", Value: 1524},
+			},
+		},
+	}
+
+	got := computeStats(raw)
+	want := Stats{
+		CPUPercent:    100,
+		MemoryUsage:   8 << 30,
+		MemoryLimit:   16 << 30,
+		MemoryPercent: 50,
+		NetworkRx:     110,
+		NetworkTx:     220,
+		BlockRead:     1024,
+		BlockWrite:    500,
+	}
+	if math.Abs(got.CPUPercent-want.CPUPercent) > 0.001 {
+		t.Fatalf("CPUPercent got %v want %v", got.CPUPercent, want.CPUPercent)
+	}
+	got.CPUPercent = want.CPUPercent
+	if got != want {
+		t.Fatalf("computeStats() got %+v want %+v", got, want)
+	}
+}
+
+func TestComputeStatsFirstSample(t *testing.T) {
+	raw := container.StatsResponse{
+		CPUStats:    container.CPUStats{CPUUsage: container.CPUUsage{TotalUsage: 100}, SystemUsage: 100, OnlineCPUs: 2},
+		MemoryStats: container.MemoryStats{Usage: 100},
+	}
+	got := computeStats(raw)
+	if got.CPUPercent != 0 || got.MemoryPercent != 0 {
+		t.Fatalf("computeStats() got %+v, want no percentages without a previous sample or limit", got)
+	}
+}
@@ -0,0 +1,21 @@
+// Package fsutil holds small file helpers shared by the bot's state stores.
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// WriteFileAtomic replaces path with data by writing a temporary file next
+// to it and renaming it into place, so a crash never leaves a half-written
+// state file behind. Missing parent directories are created.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,26 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "data.json")
+	for _, want := range []string{"first\n", "second\n"} {
+		if err := WriteFileAtomic(path, []byte(want), 0o600); err != nil {
+			t.Fatalf("WriteFileAtomic(): %v", err)
+		}
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile(): %v", err)
+		}
+		if string(got) != want {
+			t.Fatalf("file got %q want %q", got, want)
+		}
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Fatalf("temporary file left behind: %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+// Package game holds what the bot needs from a Palworld server,
+// independent of whether it is reached over RCON or the REST API.
+package game
+
+import (
+	"context"
+	"strings"
+	"unicode"
+)
+
+// MaxMessageLength caps broadcast text, in characters, on every backend so
+// a long chat message cannot overflow the in-game message box.
+const MaxMessageLength = 200
+
+// TruncateMessage cuts message to MaxMessageLength characters, ending it
+// with an ellipsis when anything was dropped.
+func TruncateMessage(message string) string {
+	runes := []rune(message)
+	if len(runes) <= MaxMessageLength {
+		return message
+	}
+	cut := strings.TrimRightFunc(string(runes[:MaxMessageLength-1]), unicode.IsSpace)
+	return cut + "…"
+}
+
+type Player struct {
+	Name      string
+	PlayerUID string
+	SteamID   string
+}
+
+type ServerInfo struct {
+	Name    string
+	Version string
+}
+
+// GameServer is implemented by rcon.Client and palapi.Client.
+type GameServer interface {
+	Info(ctx context.Context) (ServerInfo, error)
+	ShowPlayers(ctx context.Context) ([]Player, error)
+	Save(ctx context.Context) error
+	Broadcast(ctx context.Context, message string) error
+	KickPlayer(ctx context.Context, steamID string) error
+	BanPlayer(ctx context.Context, steamID string) error
+	UnBanPlayer(ctx context.Context, steamID string) error
+	Shutdown(ctx context.Context, seconds int, message string) error
+	Close() error
+}
+
+func Names(players []Player) []string {
+	names := make([]string, 0, len(players))
+	for _, player := range players {
+		names = append(names, player.Name)
+	}
+	return names
+}
@@ -0,0 +1,78 @@
+package matrix
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+func (b *Bot) handleBackup(ctx context.Context) {
+	sourceDir, err := b.saveDir(ctx)
+	if err != nil {
+		b.reply(ctx, "backup failed: "+err.Error())
+		return
+	}
+
+	status, err := b.docker.Status(ctx)
+	if err == nil && status.Running {
+		if err := b.saveWorld(ctx); err != nil {
+			b.reply(ctx, "warning: save failed before backup, archiving the last autosave: "+err.Error())
+			b.log.Warn("save failed before backup", "err", err.Error())
+		}
+	}
+
+	backupCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	archive, err := b.backups.Create(backupCtx, sourceDir)
+	cancel()
+	if err != nil {
+		b.reply(ctx, "backup failed: "+err.Error())
+		return
+	}
+
+	msg := fmt.Sprintf("backup %s created (%s)", archive.ID, formatBytes(uint64(archive.Size)))
+	removed, err := b.backups.Prune()
+	if err != nil {
+		b.log.Warn("backup retention failed", "err", err.Error())
+		msg += "; pruning old backups failed: " + err.Error()
+	} else if len(removed) > 0 {
+		msg += fmt.Sprintf("; pruned %d old backup(s)", len(removed))
+	}
+	b.reply(ctx, msg)
+}
+
+func (b *Bot) handleBackups(ctx context.Context) {
+	archives, err := b.backups.List()
+	if err != nil {
+		b.reply(ctx, "failed to list backups: "+err.Error())
+		return
+	}
+	if len(archives) == 0 {
+		b.reply(ctx, "no backups yet")
+		return
+	}
+
+	var total int64
+	lines := []string{fmt.Sprintf("Backups (%d):", len(archives))}
+	for _, archive := range archives {
+		total += archive.Size
+		lines = append(lines, fmt.Sprintf("- %s  %s  (%s)", archive.ID, formatBytes(uint64(archive.Size)), archive.Created.Local().Format("Mon 2006-01-02 15:04")))
+	}
+	lines = append(lines, "Total: "+formatBytes(uint64(total)))
+	b.reply(ctx, strings.Join(lines, "\n"))
+}
+
+// saveDir resolves the Palworld "Saved" directory on this host: either
+// configured directly or derived from the game container's mounts.
+func (b *Bot) saveDir(ctx context.Context) (string, error) {
+	if b.cfg.BackupSourceDir != "" {
+		return b.cfg.BackupSourceDir, nil
+	}
+	inspectCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	dir, err := b.docker.HostPath(inspectCtx, b.cfg.SaveContainerPath)
+	if err != nil {
+		return "", fmt.Errorf("resolve save directory (set BACKUP_SOURCE_DIR): %w", err)
+	}
+	return dir, nil
+}
@@ -6,14 +6,22 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
+	"pikabot/internal/backup"
 	"pikabot/internal/commands"
 	"pikabot/internal/config"
 	"pikabot/internal/dockerctl"
+	"pikabot/internal/fsutil"
+	"pikabot/internal/game"
 	"pikabot/internal/logx"
+	"pikabot/internal/moderation"
+	"pikabot/internal/palapi"
 	"pikabot/internal/rcon"
+	"pikabot/internal/schedule"
+	"pikabot/internal/supervisor"
 
 	"maunium.net/go/mautrix"
 	"maunium.net/go/mautrix/event"
@@ -25,11 +33,26 @@ type Bot struct {
 	log      *logx.Logger
 	matrix   *mautrix.Client
 	docker   *dockerctl.Controller
-	rcon     *rcon.Client
+	game     game.GameServer
+	metrics  *palapi.Client
 	roomID   id.RoomID
 	busy     atomic.Bool
 	allowed  map[string]struct{}
 	selfUser id.UserID
+
+	settings   *SettingsStore
+	presence   *presence
+	supervisor *supervisor.Supervisor
+	schedules  *schedule.Store
+	backups    *backup.Manager
+	chat       *chatBridge
+	auditLog   *moderation.AuditLog
+	whitelist  *moderation.Whitelist
+
+	mu             sync.Mutex
+	pendingStop    *countdown
+	pendingRestore *pendingRestore
+	tasks          sync.WaitGroup
 }
 
 func New(ctx context.Context, cfg config.Config, logger *logx.Logger) (*Bot, error) {
@@ -77,18 +100,61 @@ func New(ctx context.Context, cfg config.Config, logger *logx.Logger) (*Bot, err
 		},
 	}
 
+	settings, err := NewSettingsStore(cfg.SettingsPath(), RoomSettings{AnnouncePresence: cfg.PresenceAnnounce})
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, err := schedule.NewStore(cfg.SchedulesPath(), cfg.Schedules())
+	if err != nil {
+		return nil, err
+	}
+
+	whitelist, err := moderation.NewWhitelist(cfg.WhitelistPath())
+	if err != nil {
+		return nil, err
+	}
+
 	matrixClient.Syncer = syncer
 	matrixClient.Store = NewFileSyncStore(cfg.SyncTokenPath())
 
+	gameServer, metrics := newGameServer(cfg)
 	bot := &Bot{
-		cfg:      cfg,
-		log:      logger,
-		matrix:   matrixClient,
-		docker:   dockerController,
-		rcon:     rcon.New(cfg.RCONHost, cfg.RCONPort, cfg.RCONPass, 5*time.Second),
-		roomID:   id.RoomID(cfg.MatrixRoomID),
-		allowed:  cfg.AllowedMXIDs,
-		selfUser: matrixClient.UserID,
+		cfg:       cfg,
+		log:       logger,
+		matrix:    matrixClient,
+		docker:    dockerController,
+		game:      gameServer,
+		metrics:   metrics,
+		roomID:    id.RoomID(cfg.MatrixRoomID),
+		allowed:   cfg.AllowedMXIDs,
+		selfUser:  matrixClient.UserID,
+		settings:  settings,
+		presence:  newPresence(),
+		schedules: schedules,
+		auditLog:  moderation.NewAuditLog(cfg.AuditLogPath()),
+		whitelist: whitelist,
+		backups: backup.New(cfg.BackupDir, backup.Retention{
+			KeepLast:   cfg.BackupKeepLast,
+			KeepDaily:  cfg.BackupKeepDaily,
+			KeepWeekly: cfg.BackupKeepWeekly,
+		}),
+	}
+
+	if cfg.ChatBridge {
+		bot.chat, err = newChatBridge(cfg.ChatPatterns(), cfg.ChatBridgeRate)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.AutoRestart {
+		bot.supervisor = supervisor.New(supervisor.Config{
+			BaseDelay:  cfg.AutoRestartBaseDelay,
+			MaxDelay:   cfg.AutoRestartMaxDelay,
+			MaxCrashes: cfg.CrashLoopMax,
+			Window:     cfg.CrashLoopWindow,
+		})
 	}
 
 	syncer.OnEventType(event.EventMessage, bot.handleMessage)
@@ -100,16 +166,56 @@ func (b *Bot) Run(ctx context.Context) error {
 		return err
 	}
 
+	b.startWatchers(ctx)
+
 	b.log.Info("matrix sync started", "room_id", b.roomID.String(), "user_id", b.selfUser.String())
 	err := b.matrix.SyncWithContext(ctx)
+	b.tasks.Wait()
 	if err != nil && ctx.Err() == nil {
 		return err
 	}
 	return nil
 }
 
+func (b *Bot) startWatchers(ctx context.Context) {
+	if b.cfg.IdleShutdownAfter > 0 {
+		b.goTask(func() { b.watchIdle(ctx) })
+	}
+	b.goTask(func() { b.watchPresence(ctx) })
+	b.goTask(func() { b.watchEvents(ctx) })
+	if b.cfg.MemoryWarnPercent > 0 {
+		b.goTask(func() { b.watchMemory(ctx) })
+	}
+	if b.restartPolicy().Enabled() {
+		b.goTask(func() { b.watchRestartPolicy(ctx) })
+	}
+	b.goTask(func() { b.watchSchedules(ctx) })
+	if b.chat != nil {
+		b.goTask(func() { b.watchChat(ctx) })
+		b.goTask(func() { b.forwardChat(ctx) })
+	}
+	if b.cfg.WhitelistEnforce {
+		b.goTask(func() { b.watchWhitelist(ctx) })
+	}
+	if b.cfg.FPSWarnThreshold > 0 {
+		b.goTask(func() { b.watchPerf(ctx) })
+	}
+}
+
+func (b *Bot) goTask(fn func()) {
+	b.tasks.Add(1)
+	go func() {
+		defer b.tasks.Done()
+		fn()
+	}()
+}
+
 func (b *Bot) Close() error {
-	return b.docker.Close()
+	gameErr := b.game.Close()
+	if err := b.docker.Close(); err != nil {
+		return err
+	}
+	return gameErr
 }
 
 func (b *Bot) bootstrapSyncToken(ctx context.Context) error {
@@ -145,9 +251,6 @@ func (b *Bot) handleMessage(ctx context.Context, evt *event.Event) {
 	if evt.Sender == b.selfUser {
 		return
 	}
-	if _, ok := b.allowed[evt.Sender.String()]; !ok {
-		return
-	}
 
 	content := evt.Content.AsMessage()
 	if content == nil {
@@ -163,6 +266,70 @@ func (b *Bot) handleMessage(ctx context.Context, evt *event.Event) {
 
 	cmd := commands.Parse(content.Body, b.cfg.CommandPrefix)
 	if cmd.Type == commands.Unknown {
+		// Plain chat from anyone in the room belongs to the bridge; mistyped
+		// commands are dropped.
+		if b.chat != nil && !strings.HasPrefix(cmd.Raw, b.cfg.CommandPrefix) {
+			b.queueToGame(evt.Sender, content)
+		}
+		return
+	}
+	if _, ok := b.allowed[evt.Sender.String()]; !ok {
+		return
+	}
+
+	// These commands do not touch the container and must keep working while
+	// a long-running command holds the busy lock.
+	switch cmd.Type {
+	case commands.CancelStop:
+		b.handleCancelStop(ctx)
+		return
+	case commands.Announce:
+		b.handleAnnounce(ctx, cmd.Args)
+		return
+	case commands.Say:
+		b.handleSay(ctx, evt.Sender, cmd.Args)
+		return
+	case commands.Kick:
+		b.handleKick(ctx, evt.Sender, cmd.Args)
+		return
+	case commands.Ban:
+		b.handleBan(ctx, evt.Sender, cmd.Args)
+		return
+	case commands.Unban:
+		b.handleUnban(ctx, evt.Sender, cmd.Args)
+		return
+	case commands.Whitelist:
+		b.handleWhitelist(ctx, evt.Sender, cmd.Args)
+		return
+	case commands.Status:
+		b.handleStatus(ctx)
+		return
+	case commands.Players:
+		b.handlePlayers(ctx)
+		return
+	case commands.Stats:
+		b.handleStats(ctx)
+		return
+	case commands.Perf:
+		b.handlePerf(ctx)
+		return
+	case commands.Schedule:
+		b.handleSchedule(ctx, cmd.Args)
+		return
+	case commands.Backups:
+		b.handleBackups(ctx)
+		return
+	case commands.Restore:
+		// Takes the busy lock itself once the restore is confirmed.
+		b.handleRestore(ctx, evt.Sender, cmd.Args)
+		return
+	case commands.Logs:
+		lines, err := commands.LogLines(cmd.Args)
+		if err != nil {
+			b.reply(ctx, err.Error())
+			return
+		}
+		b.handleLogs(ctx, lines)
 		return
 	}
 
@@ -170,17 +337,38 @@ func (b *Bot) handleMessage(ctx context.Context, evt *event.Event) {
 		b.reply(ctx, "busy, try again")
 		return
 	}
+	if cmd.Type == commands.Backup {
+		// Saving and archiving can take minutes, so the backup runs in the
+		// background and releases the command lock when it is done.
+		b.goTask(func() {
+			defer b.busy.Store(false)
+			b.handleBackup(ctx)
+		})
+		return
+	}
 	defer b.busy.Store(false)
 
 	switch cmd.Type {
 	case commands.StartPal:
 		b.handleStart(ctx)
+	case commands.RestartPal:
+		b.handleRestart(ctx)
 	case commands.StopPal:
+		delay, err := commands.StopDelay(cmd.Args)
+		if err != nil {
+			b.reply(ctx, err.Error())
+			return
+		}
+		if delay > 0 {
+			b.handleScheduledStop(ctx, delay)
+			return
+		}
 		b.handleStop(ctx)
 	}
 }
 
 func (b *Bot) handleStart(ctx context.Context) {
+	b.cancelAutoRestart(ctx)
 	status, err := b.docker.Status(ctx)
 	if err != nil {
 		b.reply(ctx, "error checking server status: "+err.Error())
@@ -195,14 +383,20 @@ func (b *Bot) handleStart(ctx context.Context) {
 		return
 	}
 
+	b.supersedeCountdown(ctx)
 	if err := b.docker.Start(ctx); err != nil {
 		b.reply(ctx, "failed to start server: "+err.Error())
 		return
 	}
+	if b.supervisor != nil {
+		b.supervisor.Reset()
+	}
 	b.reply(ctx, "starting Palworld server...")
+	b.reportReady(ctx, "server is up", "startup")
 }
 
 func (b *Bot) handleStop(ctx context.Context) {
+	b.cancelAutoRestart(ctx)
 	status, err := b.docker.Status(ctx)
 	if err != nil {
 		b.reply(ctx, "error checking server status: "+err.Error())
@@ -217,23 +411,15 @@ func (b *Bot) handleStop(ctx context.Context) {
 		return
 	}
 
-	checkCtx, cancelCheck := context.WithTimeout(ctx, 5*time.Second)
-	players, err := b.rcon.ShowPlayers(checkCtx)
-	cancelCheck()
-	if err != nil {
-		b.reply(ctx, "refused to stop: could not confirm zero players via RCON")
-		b.log.Warn("rcon check failed; stop aborted", "err", err.Error())
+	if !b.confirmNoPlayers(ctx, "stop") {
 		return
 	}
-	if len(players) > 0 {
-		b.reply(ctx, "abort: players are online: "+strings.Join(players, ", "))
+	if !b.saveBeforeStop(ctx, "stop") {
 		return
 	}
 
-	stopCtx, cancelStop := context.WithTimeout(ctx, 30*time.Second)
-	err = b.docker.Stop(stopCtx, 30*time.Second)
-	cancelStop()
-	if err != nil {
+	b.supersedeCountdown(ctx)
+	if err := b.stopContainer(ctx); err != nil {
 		b.reply(ctx, "failed to stop server: "+err.Error())
 		return
 	}
@@ -241,12 +427,73 @@ func (b *Bot) handleStop(ctx context.Context) {
 	b.reply(ctx, "server stopped")
 }
 
+// confirmNoPlayers is the fail-safe shared by every command that takes the
+// server down: it only passes on a confirmed zero player count.
+func (b *Bot) confirmNoPlayers(ctx context.Context, action string) bool {
+	players, err := b.onlinePlayers(ctx)
+	if err != nil {
+		b.reply(ctx, "refused to "+action+": could not confirm zero players from the game server")
+		b.log.Warn("player check failed; "+action+" aborted", "err", err.Error())
+		return false
+	}
+	if len(players) > 0 {
+		b.reply(ctx, "abort: players are online: "+strings.Join(game.Names(players), ", "))
+		return false
+	}
+	return true
+}
+
+func (b *Bot) saveWorld(ctx context.Context) error {
+	saveCtx, cancelSave := context.WithTimeout(ctx, 30*time.Second)
+	defer cancelSave()
+	return b.game.Save(saveCtx)
+}
+
+// saveBeforeStop runs a world save and waits for it before the container is
+// stopped or restarted. A failed save is reported and, depending on
+// SAVE_FAILURE_ABORTS_STOP, either vetoes the action or is only a warning.
+func (b *Bot) saveBeforeStop(ctx context.Context, action string) bool {
+	err := b.saveWorld(ctx)
+	if err == nil {
+		return true
+	}
+	b.log.Warn("save failed before "+action, "err", err.Error(), "aborted", b.cfg.SaveFailureAbortsStop)
+	if b.cfg.SaveFailureAbortsStop {
+		b.reply(ctx, "refused to "+action+": save failed: "+err.Error())
+		return false
+	}
+	b.reply(ctx, "warning: save failed before "+action+": "+err.Error())
+	return true
+}
+
+func (b *Bot) onlinePlayers(ctx context.Context) ([]game.Player, error) {
+	checkCtx, cancelCheck := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelCheck()
+	return b.game.ShowPlayers(checkCtx)
+}
+
+func (b *Bot) stopContainer(ctx context.Context) error {
+	stopCtx, cancelStop := context.WithTimeout(ctx, 30*time.Second)
+	defer cancelStop()
+	return b.docker.Stop(stopCtx, 30*time.Second)
+}
+
 func (b *Bot) reply(ctx context.Context, text string) {
 	if _, err := b.matrix.SendText(ctx, b.roomID, text); err != nil {
 		b.log.Error("failed sending matrix message", "err", err.Error())
 	}
 }
 
+// newGameServer picks the game API backend; both satisfy game.GameServer.
+// The REST client is returned either way, since metrics only exist there.
+func newGameServer(cfg config.Config) (game.GameServer, *palapi.Client) {
+	api := palapi.New(cfg.RESTAPIURL, cfg.RESTAPIUser, cfg.RESTAPIPass, 10*time.Second)
+	if cfg.GameAPI == "rest" {
+		return api, api
+	}
+	return rcon.New(cfg.RCONHost, cfg.RCONPort, cfg.RCONPass, 5*time.Second), api
+}
+
 func resolveAccessToken(cfg config.Config) (string, error) {
 	if token := strings.TrimSpace(cfg.MatrixAccessToken); token != "" {
 		return token, nil
@@ -289,7 +536,7 @@ func readSecretFile(path string) string {
 }
 
 func writeSecretFile(path string, data []byte) error {
-	return writeFileAtomically(path, data, 0o600)
+	return fsutil.WriteFileAtomic(path, data, 0o600)
 }
 
 func tokenFileExists(path string) bool {
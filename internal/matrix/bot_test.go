@@ -0,0 +1,209 @@
+package matrix
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"pikabot/internal/config"
+	"pikabot/internal/dockerctl"
+	"pikabot/internal/game"
+	"pikabot/internal/logx"
+	"pikabot/internal/moderation"
+
+	"maunium.net/go/mautrix"
+	"maunium.net/go/mautrix/id"
+)
+
+// fakeGame records the calls the bot makes against the game server.
+type fakeGame struct {
+	mu         sync.Mutex
+	players    []game.Player
+	kicked     []string
+	banned     []string
+	unbanned   []string
+	broadcasts []string
+	saves      int
+}
+
+func (g *fakeGame) Info(context.Context) (game.ServerInfo, error) {
+	return game.ServerInfo{Name: "test", Version: "v0"}, nil
+}
+
+func (g *fakeGame) ShowPlayers(context.Context) ([]game.Player, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]game.Player(nil), g.players...), nil
+}
+
+func (g *fakeGame) Save(context.Context) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.saves++
+	return nil
+}
+
+func (g *fakeGame) Broadcast(_ context.Context, message string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.broadcasts = append(g.broadcasts, message)
+	return nil
+}
+
+func (g *fakeGame) KickPlayer(_ context.Context, steamID string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.kicked = append(g.kicked, steamID)
+	return nil
+}
+
+func (g *fakeGame) BanPlayer(_ context.Context, steamID string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.banned = append(g.banned, steamID)
+	return nil
+}
+
+func (g *fakeGame) UnBanPlayer(_ context.Context, steamID string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.unbanned = append(g.unbanned, steamID)
+	return nil
+}
+
+func (g *fakeGame) Shutdown(context.Context, int, string) error { return nil }
+func (g *fakeGame) Close() error                                { return nil }
+
+// fakeRoom is a homeserver that accepts every message sent to it.
+type fakeRoom struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (r *fakeRoom) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPut || !strings.Contains(req.URL.Path, "/send/") {
+		http.NotFound(w, req)
+		return
+	}
+	var content struct {
+		Body string `json:"body"`
+	}
+	_ = json.NewDecoder(req.Body).Decode(&content)
+	r.mu.Lock()
+	r.messages = append(r.messages, content.Body)
+	r.mu.Unlock()
+	_, _ = w.Write([]byte(`{"event_id":"$event"}`))
+}
+
+func (r *fakeRoom) sent() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.messages...)
+}
+
+func newTestBot(t *testing.T, g *fakeGame) (*Bot, *fakeRoom) {
+	t.Helper()
+	room := &fakeRoom{}
+	srv := httptest.NewServer(room)
+	t.Cleanup(srv.Close)
+
+	client, err := mautrix.NewClient(srv.URL, "@bot:example.org", "token")
+	if err != nil {
+		t.Fatalf("NewClient(): %v", err)
+	}
+	dir := t.TempDir()
+	b := &Bot{
+		cfg:      config.Config{CommandPrefix: "!", DataDir: dir},
+		log:      logx.New(logx.Error),
+		matrix:   client,
+		game:     g,
+		roomID:   id.RoomID("!room:example.org"),
+		selfUser: client.UserID,
+		auditLog: moderation.NewAuditLog(filepath.Join(dir, "audit.jsonl")),
+	}
+	t.Cleanup(b.tasks.Wait)
+	return b, room
+}
+
+// fakeDocker serves the few Docker Engine API calls the bot makes for one
+// container.
+type fakeDocker struct {
+	mu       sync.Mutex
+	running  bool
+	requests []string
+}
+
+func (d *fakeDocker) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	path := req.URL.Path
+	// Drop the API version prefix, e.g. /v1.45/containers/...
+	if strings.HasPrefix(path, "/v1.") {
+		path = path[strings.Index(path[1:], "/")+1:]
+	}
+	w.Header().Set("Api-Version", "1.45")
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	switch {
+	case path == "/_ping":
+		_, _ = w.Write([]byte("OK"))
+		return
+	case req.Method == http.MethodGet && path == "/containers/palworld/json":
+		status := "exited"
+		if d.running {
+			status = "running"
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"Id":    "palworld",
+			"State": map[string]any{"Status": status, "Running": d.running, "StartedAt": time.Now().Format(time.RFC3339Nano)},
+		})
+		return
+	case req.Method == http.MethodPost && path == "/containers/palworld/start":
+		d.running = true
+	case req.Method == http.MethodPost && path == "/containers/palworld/stop":
+		d.running = false
+	case req.Method == http.MethodPost && path == "/containers/palworld/restart":
+		d.running = true
+	default:
+		http.NotFound(w, req)
+		return
+	}
+	d.requests = append(d.requests, strings.TrimPrefix(path, "/containers/palworld/"))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// calls lists the container actions (start, stop, restart) in order.
+func (d *fakeDocker) calls() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.requests...)
+}
+
+func (d *fakeDocker) setRunning(running bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.running = running
+}
+
+func withFakeDocker(t *testing.T, b *Bot, running bool) *fakeDocker {
+	t.Helper()
+	d := &fakeDocker{running: running}
+	srv := httptest.NewServer(d)
+	t.Cleanup(srv.Close)
+
+	t.Setenv("DOCKER_HOST", "tcp://"+srv.Listener.Addr().String())
+	t.Setenv("DOCKER_API_VERSION", "")
+	ctl, err := dockerctl.New("palworld")
+	if err != nil {
+		t.Fatalf("dockerctl.New(): %v", err)
+	}
+	t.Cleanup(func() { ctl.Close() })
+	b.cfg.DockerContainerName = "palworld"
+	b.docker = ctl
+	return d
+}
@@ -0,0 +1,205 @@
+package matrix
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"pikabot/internal/chat"
+
+	"maunium.net/go/mautrix/event"
+	"maunium.net/go/mautrix/id"
+)
+
+const (
+	chatRetryDelay = 10 * time.Second
+	chatBurst      = 5
+	chatEchoTTL    = time.Minute
+	displayNameTTL = 10 * time.Minute
+	chatGamePrefix = "[game] "
+	chatQueueSize  = 32
+)
+
+// chatBridge relays plain room messages into the game and in-game chat from
+// the container log back into the room. It is independent of the command
+// path: handleMessage only queues messages that are not commands, and
+// forwardChat relays them so the sync loop never waits on the game server.
+type chatBridge struct {
+	parser *chat.Parser
+	queue  chan chatOut
+
+	mu              sync.Mutex
+	toGame          *chat.Limiter
+	toMatrix        *chat.Limiter
+	echo            *chat.Echo
+	names           map[id.UserID]cachedName
+	throttledGame   bool
+	throttledMatrix bool
+}
+
+type chatOut struct {
+	sender  id.UserID
+	content *event.MessageEventContent
+}
+
+type cachedName struct {
+	name    string
+	expires time.Time
+}
+
+func newChatBridge(patterns []string, perMinute int) (*chatBridge, error) {
+	parser, err := chat.NewParser(patterns)
+	if err != nil {
+		return nil, err
+	}
+	return &chatBridge{
+		parser:   parser,
+		queue:    make(chan chatOut, chatQueueSize),
+		toGame:   chat.NewLimiter(perMinute, chatBurst),
+		toMatrix: chat.NewLimiter(perMinute, chatBurst),
+		echo:     chat.NewEcho(chatEchoTTL),
+		names:    make(map[id.UserID]cachedName),
+	}, nil
+}
+
+// queueToGame hands a room message to forwardChat without blocking. When
+// the game server is too slow to keep up, new messages are dropped.
+func (b *Bot) queueToGame(sender id.UserID, content *event.MessageEventContent) {
+	select {
+	case b.chat.queue <- chatOut{sender: sender, content: content}:
+	default:
+		b.log.Debug("chat relay queue full; dropping message", "sender", sender.String())
+	}
+}
+
+func (b *Bot) forwardChat(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case out := <-b.chat.queue:
+			b.relayToGame(ctx, out.sender, out.content)
+		}
+	}
+}
+
+// relayToGame broadcasts a non-command room message in-game as
+// "<display name>: <text>". Edits, notices (usually other bots) and reply
+// quotes are not relayed.
+func (b *Bot) relayToGame(ctx context.Context, sender id.UserID, content *event.MessageEventContent) {
+	if content.MsgType != event.MsgText && content.MsgType != event.MsgEmote {
+		return
+	}
+	if content.RelatesTo.GetReplaceID() != "" {
+		return
+	}
+	text := content.Body
+	if content.RelatesTo.GetReplyTo() != "" {
+		text = event.TrimReplyFallbackText(text)
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+
+	name := b.chatName(ctx, sender)
+	message := name + ": " + text
+	if content.MsgType == event.MsgEmote {
+		message = "* " + name + " " + text
+	}
+
+	bridge := b.chat
+	bridge.mu.Lock()
+	allowed := bridge.toGame.Allow(time.Now())
+	notify := !allowed && !bridge.throttledGame
+	bridge.throttledGame = !allowed
+	if allowed {
+		bridge.echo.Remember(message, time.Now())
+	}
+	bridge.mu.Unlock()
+	if !allowed {
+		if notify {
+			b.notice(ctx, "chat bridge: too many messages, some are not relayed in-game")
+		}
+		return
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := b.game.Broadcast(sendCtx, message); err != nil {
+		b.log.Debug("chat relay to game failed", "err", err.Error())
+	}
+}
+
+// watchChat follows the container log for chat lines. The stream ends
+// whenever the container stops, so it is resubscribed from where it left off.
+func (b *Bot) watchChat(ctx context.Context) {
+	since := time.Now()
+	for {
+		lines, errs := b.docker.FollowLogs(ctx, since)
+		for line := range lines {
+			if msg, ok := b.chat.parser.Parse(line); ok {
+				b.relayToMatrix(ctx, msg)
+			}
+		}
+		if err := <-errs; err != nil && ctx.Err() == nil {
+			b.log.Debug("container log stream ended; resubscribing", "err", err.Error())
+		}
+		since = time.Now()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(chatRetryDelay):
+		}
+	}
+}
+
+func (b *Bot) relayToMatrix(ctx context.Context, msg chat.Message) {
+	bridge := b.chat
+	now := time.Now()
+	bridge.mu.Lock()
+	// Our own broadcasts may be logged like player chat; never send them back.
+	if bridge.echo.Seen(msg.Name+": "+msg.Text, now) || bridge.echo.Seen(msg.Text, now) {
+		bridge.mu.Unlock()
+		return
+	}
+	allowed := bridge.toMatrix.Allow(now)
+	notify := !allowed && !bridge.throttledMatrix
+	bridge.throttledMatrix = !allowed
+	bridge.mu.Unlock()
+	if !allowed {
+		if notify {
+			b.log.Warn("chat bridge rate limit reached; dropping in-game messages")
+			b.notice(ctx, "chat bridge: too much in-game chat, some messages are not relayed")
+		}
+		return
+	}
+	b.notice(ctx, chatGamePrefix+msg.Name+": "+msg.Text)
+}
+
+// chatName caches display names so a busy chat does not hit the profile API
+// for every message.
+func (b *Bot) chatName(ctx context.Context, sender id.UserID) string {
+	bridge := b.chat
+	bridge.mu.Lock()
+	cached, ok := bridge.names[sender]
+	bridge.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.name
+	}
+	name := b.displayName(ctx, sender)
+	bridge.mu.Lock()
+	bridge.names[sender] = cachedName{name: name, expires: time.Now().Add(displayNameTTL)}
+	bridge.mu.Unlock()
+	return name
+}
+
+// notice posts as m.notice, which other bots (and this one) do not treat as
+// chat to relay.
+func (b *Bot) notice(ctx context.Context, text string) {
+	if _, err := b.matrix.SendNotice(ctx, b.roomID, text); err != nil {
+		b.log.Error("failed sending matrix notice", "err", err.Error())
+	}
+}
@@ -0,0 +1,246 @@
+package matrix
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+var countdownWarnings = []time.Duration{
+	10 * time.Minute,
+	5 * time.Minute,
+	time.Minute,
+	30 * time.Second,
+}
+
+type countdown struct {
+	cancel   context.CancelFunc
+	deadline time.Time
+	// restart ends the countdown with a restart instead of a stop.
+	restart bool
+	// onCancel, when set, runs after cancelstop aborted the countdown.
+	onCancel func()
+}
+
+func (cd *countdown) action() string {
+	if cd.restart {
+		return "restart"
+	}
+	return "shutdown"
+}
+
+func (cd *countdown) warning(remaining time.Duration) string {
+	if cd.restart {
+		return "Server restarting in " + formatDuration(remaining)
+	}
+	return "Server shutting down in " + formatDuration(remaining)
+}
+
+func (b *Bot) handleScheduledStop(ctx context.Context, delay time.Duration) {
+	status, err := b.docker.Status(ctx)
+	if err != nil {
+		b.reply(ctx, "error checking server status: "+err.Error())
+		return
+	}
+	if !status.Exists {
+		b.reply(ctx, "configured container was not found")
+		return
+	}
+	if !status.Running {
+		b.reply(ctx, "server is already stopped")
+		return
+	}
+
+	if cd := b.startCountdown(ctx, delay, false, nil); cd != nil {
+		b.reply(ctx, "a "+cd.action()+" is already scheduled in "+formatDuration(time.Until(cd.deadline))+"; use cancelstop first")
+		return
+	}
+	b.reply(ctx, "server will stop in "+formatDuration(delay)+" (cancel with "+b.cfg.CommandPrefix+"cancelstop)")
+}
+
+// startCountdown schedules a countdown unless one is already pending, in
+// which case the pending one is returned and nothing is scheduled.
+func (b *Bot) startCountdown(ctx context.Context, delay time.Duration, restart bool, onCancel func()) *countdown {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.pendingStop != nil {
+		return b.pendingStop
+	}
+	cdCtx, cancel := context.WithCancel(ctx)
+	cd := &countdown{cancel: cancel, deadline: time.Now().Add(delay), restart: restart, onCancel: onCancel}
+	b.pendingStop = cd
+	b.goTask(func() { b.runCountdown(cdCtx, ctx, cd) })
+	return nil
+}
+
+func (b *Bot) stopPending() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.pendingStop != nil
+}
+
+func (b *Bot) handleCancelStop(ctx context.Context) {
+	cd := b.cancelCountdown()
+	if cd == nil {
+		b.reply(ctx, "no shutdown or restart is scheduled")
+		return
+	}
+	if cd.restart {
+		b.broadcast(ctx, "Server restart cancelled")
+	} else {
+		b.broadcast(ctx, "Server shutdown cancelled")
+	}
+	b.reply(ctx, "scheduled "+cd.action()+" cancelled")
+}
+
+// cancelCountdown aborts the pending countdown, if any, and returns it.
+func (b *Bot) cancelCountdown() *countdown {
+	b.mu.Lock()
+	cd := b.pendingStop
+	b.pendingStop = nil
+	b.mu.Unlock()
+
+	if cd == nil {
+		return nil
+	}
+	cd.cancel()
+	if cd.onCancel != nil {
+		cd.onCancel()
+	}
+	return cd
+}
+
+// supersedeCountdown drops a pending countdown right before an immediate
+// start, stop or restart, which would otherwise be undone when it expires.
+func (b *Bot) supersedeCountdown(ctx context.Context) {
+	if cd := b.cancelCountdown(); cd != nil {
+		b.reply(ctx, "scheduled "+cd.action()+" cancelled")
+	}
+}
+
+// runCountdown waits out the countdown in the background so the busy lock is
+// only taken for the final save and stop. cdCtx is cancelled by cancelstop;
+// ctx stays valid for replies after that.
+func (b *Bot) runCountdown(cdCtx, ctx context.Context, cd *countdown) {
+	defer func() {
+		b.mu.Lock()
+		if b.pendingStop == cd {
+			b.pendingStop = nil
+		}
+		b.mu.Unlock()
+		cd.cancel()
+	}()
+
+	b.broadcast(cdCtx, cd.warning(time.Until(cd.deadline)))
+	for _, warning := range countdownWarnings {
+		at := cd.deadline.Add(-warning)
+		if time.Until(at) <= 0 {
+			continue
+		}
+		if !sleepUntil(cdCtx, at) {
+			return
+		}
+		b.broadcast(cdCtx, cd.warning(warning))
+	}
+	if !sleepUntil(cdCtx, cd.deadline) {
+		return
+	}
+
+	if !b.acquireBusy(cdCtx) {
+		return
+	}
+	defer b.busy.Store(false)
+
+	// Past this point cancelstop can no longer abort the countdown.
+	b.mu.Lock()
+	active := b.pendingStop == cd
+	if active {
+		b.pendingStop = nil
+	}
+	b.mu.Unlock()
+	if !active {
+		return
+	}
+
+	// The server may have been stopped by other means in the meantime.
+	status, err := b.docker.Status(ctx)
+	if err != nil {
+		b.reply(ctx, "countdown finished, but checking server status failed; "+cd.action()+" skipped: "+err.Error())
+		return
+	}
+	if !status.Exists || !status.Running {
+		b.reply(ctx, "countdown finished, but the server is not running; "+cd.action()+" skipped")
+		return
+	}
+
+	b.reply(ctx, "countdown finished, saving world")
+	if !b.saveBeforeStop(ctx, cd.action()) {
+		b.broadcast(ctx, "Server "+cd.action()+" aborted")
+		return
+	}
+
+	if cd.restart {
+		b.restartContainer(ctx)
+		return
+	}
+	if err := b.stopContainer(ctx); err != nil {
+		b.reply(ctx, "failed to stop server: "+err.Error())
+		return
+	}
+	b.reply(ctx, "server stopped")
+}
+
+func (b *Bot) broadcast(ctx context.Context, message string) {
+	broadcastCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := b.game.Broadcast(broadcastCtx, message); err != nil {
+		b.log.Warn("broadcast failed", "message", message, "err", err.Error())
+	}
+}
+
+// acquireBusy waits for the command lock instead of refusing like
+// handleMessage does, since a background task cannot ask the user to retry.
+func (b *Bot) acquireBusy(ctx context.Context) bool {
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for !b.busy.CompareAndSwap(false, true) {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+	return true
+}
+
+func sleepUntil(ctx context.Context, at time.Time) bool {
+	timer := time.NewTimer(time.Until(at))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d >= time.Hour && d%time.Hour == 0:
+		return plural(int(d/time.Hour), "hour")
+	case d >= time.Minute && d%time.Minute == 0:
+		return plural(int(d/time.Minute), "minute")
+	case d < time.Minute:
+		return plural(int(d/time.Second), "second")
+	default:
+		return d.String()
+	}
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
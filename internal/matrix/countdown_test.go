@@ -0,0 +1,56 @@
+package matrix
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestImmediateStopCancelsCountdown(t *testing.T) {
+	b, room := newTestBot(t, &fakeGame{})
+	docker := withFakeDocker(t, b, true)
+	ctx := context.Background()
+
+	b.handleScheduledStop(ctx, 30*time.Minute)
+	if !b.stopPending() {
+		t.Fatal("scheduled stop did not start a countdown")
+	}
+	b.handleStop(ctx)
+	if b.stopPending() {
+		t.Fatal("countdown still pending after an immediate stop")
+	}
+	b.handleStart(ctx)
+	b.tasks.Wait()
+
+	if want := []string{"stop", "start"}; !reflect.DeepEqual(docker.calls(), want) {
+		t.Fatalf("docker calls %v want %v", docker.calls(), want)
+	}
+	if !slices.Contains(room.sent(), "scheduled shutdown cancelled") {
+		t.Fatalf("room messages %q do not report the cancelled countdown", room.sent())
+	}
+}
+
+func TestCountdownSkipsStoppedServer(t *testing.T) {
+	g := &fakeGame{}
+	b, room := newTestBot(t, g)
+	docker := withFakeDocker(t, b, true)
+	ctx := context.Background()
+
+	if cd := b.startCountdown(ctx, 50*time.Millisecond, false, nil); cd != nil {
+		t.Fatal("startCountdown() found a pending countdown")
+	}
+	docker.setRunning(false)
+	b.tasks.Wait()
+
+	if calls := docker.calls(); len(calls) != 0 {
+		t.Fatalf("docker calls %v want none", calls)
+	}
+	if g.saves != 0 {
+		t.Fatalf("world saved %d times, want 0", g.saves)
+	}
+	if !slices.Contains(room.sent(), "countdown finished, but the server is not running; shutdown skipped") {
+		t.Fatalf("room messages %q do not report the skipped shutdown", room.sent())
+	}
+}
@@ -0,0 +1,121 @@
+package matrix
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"pikabot/internal/dockerctl"
+	"pikabot/internal/supervisor"
+
+	"github.com/docker/docker/api/types/events"
+)
+
+const eventsRetryDelay = 5 * time.Second
+
+func (b *Bot) watchEvents(ctx context.Context) {
+	for {
+		stream, errs := b.docker.Events(ctx)
+		for evt := range stream {
+			b.handleContainerEvent(ctx, evt)
+		}
+		if err := <-errs; err != nil && ctx.Err() == nil {
+			b.log.Warn("docker event stream ended; resubscribing", "err", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(eventsRetryDelay):
+		}
+	}
+}
+
+func (b *Bot) handleContainerEvent(ctx context.Context, evt dockerctl.Event) {
+	b.log.Info("container event", "action", string(evt.Action), "exit_code", evt.ExitCode, "oom_killed", evt.OOMKilled, "expected", evt.Expected)
+
+	msg, ok := containerAlert(evt)
+	if !ok {
+		return
+	}
+	b.reply(ctx, msg)
+	if evt.Action == events.ActionDie && b.supervisor != nil {
+		b.autoRestart(ctx)
+	}
+}
+
+// containerAlert returns the room alert for a container event. Events the
+// bot caused itself through Stop or Restart are not alerted.
+func containerAlert(evt dockerctl.Event) (string, bool) {
+	if evt.Expected {
+		return "", false
+	}
+	switch evt.Action {
+	case events.ActionDie:
+		return fmt.Sprintf("alert: Palworld container exited unexpectedly (exit code %d, OOMKilled: %t)", evt.ExitCode, evt.OOMKilled), true
+	case events.ActionRestart:
+		return "alert: Palworld container was restarted by Docker", true
+	}
+	return "", false
+}
+
+func (b *Bot) autoRestart(ctx context.Context) {
+	decision := b.supervisor.RecordCrash()
+	if decision.GiveUp {
+		b.alert(ctx, fmt.Sprintf("crash loop: container crashed %d times within %s, auto-restart disabled; manual intervention needed",
+			decision.Crashes, formatDuration(b.cfg.CrashLoopWindow)))
+		return
+	}
+
+	b.reply(ctx, fmt.Sprintf("auto-restart in %s (crash %d; giving up after %d within %s)",
+		formatDuration(decision.Delay), decision.Crashes, b.cfg.CrashLoopMax, formatDuration(b.cfg.CrashLoopWindow)))
+	b.goTask(func() {
+		if err := b.supervisor.Wait(ctx, decision); err != nil {
+			return
+		}
+		if !b.acquireBusy(ctx) {
+			return
+		}
+		defer b.busy.Store(false)
+		b.runAutoRestart(ctx, decision)
+	})
+}
+
+// runAutoRestart starts the crashed container under the busy lock, unless a
+// manual start, stop or restore ran during the backoff or the container came
+// back by other means.
+func (b *Bot) runAutoRestart(ctx context.Context, decision supervisor.Decision) {
+	if err := b.supervisor.Claim(decision); err != nil {
+		return
+	}
+	status, err := b.docker.Status(ctx)
+	if err != nil {
+		b.reply(ctx, "auto-restart failed: error checking server status: "+err.Error())
+		return
+	}
+	if !status.Exists {
+		b.reply(ctx, "auto-restart skipped: configured container was not found")
+		return
+	}
+	if status.Running {
+		b.reply(ctx, "auto-restart skipped: server is already running")
+		return
+	}
+
+	if err := b.docker.Start(ctx); err != nil {
+		if ctx.Err() == nil {
+			b.reply(ctx, "auto-restart failed: "+err.Error())
+		}
+		return
+	}
+	b.reply(ctx, "auto-restart: starting Palworld server...")
+	b.reportReady(ctx, "server is up after auto-restart", "auto-restart")
+}
+
+// cancelAutoRestart drops a crash restart still waiting out its backoff, so
+// it cannot undo a manual start, stop or restore.
+func (b *Bot) cancelAutoRestart(ctx context.Context) {
+	if b.supervisor != nil && b.supervisor.Cancel() {
+		b.reply(ctx, "pending auto-restart cancelled")
+	}
+}
@@ -0,0 +1,85 @@
+package matrix
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"pikabot/internal/dockerctl"
+	"pikabot/internal/supervisor"
+
+	"github.com/docker/docker/api/types/events"
+)
+
+func TestContainerAlert(t *testing.T) {
+	tests := []struct {
+		name string
+		evt  dockerctl.Event
+		want bool
+	}{
+		{name: "crash", evt: dockerctl.Event{Action: events.ActionDie, ExitCode: 137}, want: true},
+		{name: "own stop", evt: dockerctl.Event{Action: events.ActionDie, Expected: true}},
+		{name: "docker restart", evt: dockerctl.Event{Action: events.ActionRestart}, want: true},
+		{name: "own restart", evt: dockerctl.Event{Action: events.ActionRestart, Expected: true}},
+		{name: "start", evt: dockerctl.Event{Action: events.ActionStart}},
+		{name: "oom before die", evt: dockerctl.Event{Action: events.ActionOOM}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, ok := containerAlert(tt.evt)
+			if ok != tt.want || (ok && msg == "") {
+				t.Fatalf("containerAlert(%+v) got %q, %v want alert %v", tt.evt, msg, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestManualStopCancelsPendingAutoRestart(t *testing.T) {
+	b, room := newTestBot(t, &fakeGame{})
+	docker := withFakeDocker(t, b, false)
+	b.supervisor = supervisor.New(supervisor.Config{BaseDelay: 50 * time.Millisecond})
+	ctx := context.Background()
+
+	b.autoRestart(ctx)
+	b.handleStop(ctx)
+	b.tasks.Wait()
+
+	if calls := docker.calls(); len(calls) != 0 {
+		t.Fatalf("docker calls %v want none", calls)
+	}
+	if !slices.Contains(room.sent(), "pending auto-restart cancelled") {
+		t.Fatalf("room messages %q do not report the cancelled auto-restart", room.sent())
+	}
+}
+
+func TestAutoRestartWaitsForBusyLock(t *testing.T) {
+	b, room := newTestBot(t, &fakeGame{})
+	docker := withFakeDocker(t, b, false)
+	b.supervisor = supervisor.New(supervisor.Config{BaseDelay: 10 * time.Millisecond})
+	ctx := context.Background()
+
+	// A command holding the lock brings the server back before the backoff
+	// restart gets its turn.
+	b.busy.Store(true)
+	b.autoRestart(ctx)
+	time.Sleep(50 * time.Millisecond)
+	docker.setRunning(true)
+	b.busy.Store(false)
+	b.tasks.Wait()
+
+	if calls := docker.calls(); len(calls) != 0 {
+		t.Fatalf("docker calls %v want none", calls)
+	}
+	if !slices.Contains(room.sent(), "auto-restart skipped: server is already running") {
+		t.Fatalf("room messages %q do not report the skipped auto-restart", room.sent())
+	}
+
+	docker.setRunning(false)
+	b.autoRestart(ctx)
+	b.tasks.Wait()
+	if want := []string{"start"}; !reflect.DeepEqual(docker.calls(), want) {
+		t.Fatalf("docker calls %v want %v", docker.calls(), want)
+	}
+}
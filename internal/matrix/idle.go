@@ -0,0 +1,125 @@
+package matrix
+
+import (
+	"context"
+	"time"
+
+	"pikabot/internal/game"
+)
+
+type idleEvent int
+
+const (
+	idleNone idleEvent = iota
+	idleBecameEmpty
+	idleExpired
+)
+
+type idleTracker struct {
+	grace      time.Duration
+	emptySince time.Time
+}
+
+// observe records one poll. Only a running server with a confirmed empty
+// player list counts as idle; a failed player check resets the timer like a
+// join would.
+func (t *idleTracker) observe(now time.Time, running bool, players []game.Player, err error) idleEvent {
+	if !running || err != nil || len(players) > 0 {
+		t.reset()
+		return idleNone
+	}
+	if t.emptySince.IsZero() {
+		t.emptySince = now
+		if t.grace > 0 {
+			return idleBecameEmpty
+		}
+	}
+	if now.Sub(t.emptySince) >= t.grace {
+		return idleExpired
+	}
+	return idleNone
+}
+
+func (t *idleTracker) reset() {
+	t.emptySince = time.Time{}
+}
+
+func (b *Bot) watchIdle(ctx context.Context) {
+	tracker := &idleTracker{grace: b.cfg.IdleShutdownAfter}
+	ticker := time.NewTicker(b.cfg.IdlePollInterval)
+	defer ticker.Stop()
+
+	b.log.Info("idle shutdown watcher started", "after", b.cfg.IdleShutdownAfter.String(), "interval", b.cfg.IdlePollInterval.String())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.checkIdle(ctx, tracker)
+		}
+	}
+}
+
+func (b *Bot) checkIdle(ctx context.Context, tracker *idleTracker) {
+	if b.stopPending() {
+		tracker.reset()
+		return
+	}
+
+	status, err := b.docker.Status(ctx)
+	if err != nil {
+		b.log.Warn("idle check: docker status failed", "err", err.Error())
+		tracker.reset()
+		return
+	}
+	running := status.Exists && status.Running
+
+	var players []game.Player
+	if running {
+		players, err = b.onlinePlayers(ctx)
+		if err != nil {
+			b.log.Warn("idle check: player check failed", "err", err.Error())
+		}
+	}
+
+	switch tracker.observe(time.Now(), running, players, err) {
+	case idleBecameEmpty:
+		b.reply(ctx, "server is empty; it will be stopped in "+formatDuration(b.cfg.IdleShutdownAfter)+" unless someone joins")
+	case idleExpired:
+		if b.stopIdle(ctx) {
+			tracker.reset()
+		}
+	}
+}
+
+// stopIdle reports whether the idle state was consumed, either by stopping
+// or by finding players after all. A busy bot is retried on the next poll.
+func (b *Bot) stopIdle(ctx context.Context) bool {
+	if !b.busy.CompareAndSwap(false, true) {
+		return false
+	}
+	defer b.busy.Store(false)
+
+	// Confirm once more under the lock, same rule as handleStop: no
+	// confirmed zero, no stop.
+	players, err := b.onlinePlayers(ctx)
+	if err != nil {
+		b.log.Warn("idle stop aborted: player check failed", "err", err.Error())
+		return true
+	}
+	if len(players) > 0 {
+		return true
+	}
+
+	b.reply(ctx, "no players for "+formatDuration(b.cfg.IdleShutdownAfter)+", saving and stopping server")
+	if !b.saveBeforeStop(ctx, "stop") {
+		return true
+	}
+
+	if err := b.stopContainer(ctx); err != nil {
+		b.reply(ctx, "failed to stop idle server: "+err.Error())
+		return true
+	}
+	b.reply(ctx, "server stopped (idle)")
+	return true
+}
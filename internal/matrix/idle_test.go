@@ -0,0 +1,73 @@
+package matrix
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"pikabot/internal/game"
+)
+
+func TestIdleTracker(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	alice := []game.Player{{Name: "Alice", PlayerUID: "uid1", SteamID: "steam1"}}
+	rconErr := errors.New("rcon down")
+
+	type step struct {
+		offset  time.Duration
+		running bool
+		players []game.Player
+		err     error
+		want    idleEvent
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "empty for the whole grace period",
+			steps: []step{
+				{offset: 0, running: true, want: idleBecameEmpty},
+				{offset: 10 * time.Minute, running: true, want: idleNone},
+				{offset: 30 * time.Minute, running: true, want: idleExpired},
+			},
+		},
+		{
+			name: "player joining resets the timer",
+			steps: []step{
+				{offset: 0, running: true, want: idleBecameEmpty},
+				{offset: 20 * time.Minute, running: true, players: alice, want: idleNone},
+				{offset: 25 * time.Minute, running: true, want: idleBecameEmpty},
+				{offset: 50 * time.Minute, running: true, want: idleNone},
+				{offset: 55 * time.Minute, running: true, want: idleExpired},
+			},
+		},
+		{
+			name: "rcon errors never count as empty",
+			steps: []step{
+				{offset: 0, running: true, want: idleBecameEmpty},
+				{offset: 29 * time.Minute, running: true, err: rconErr, want: idleNone},
+				{offset: 31 * time.Minute, running: true, want: idleBecameEmpty},
+			},
+		},
+		{
+			name: "stopped server is not idle",
+			steps: []step{
+				{offset: 0, running: false, want: idleNone},
+				{offset: time.Hour, running: false, want: idleNone},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := &idleTracker{grace: 30 * time.Minute}
+			for i, s := range tt.steps {
+				got := tracker.observe(start.Add(s.offset), s.running, s.players, s.err)
+				if got != s.want {
+					t.Fatalf("step %d: observe() got %v want %v", i, got, s.want)
+				}
+			}
+		})
+	}
+}
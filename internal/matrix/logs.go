@@ -0,0 +1,84 @@
+package matrix
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"pikabot/internal/dockerctl"
+
+	"maunium.net/go/mautrix/event"
+)
+
+const (
+	// Larger outputs are uploaded as a file instead of a code block.
+	logsInlineMaxBytes = 3000
+	logsInlineMaxLines = 40
+	logsUploadMaxBytes = 256 * 1024
+)
+
+func (b *Bot) handleLogs(ctx context.Context, lines int) {
+	logsCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	text, err := b.docker.Logs(logsCtx, lines)
+	cancel()
+	if err != nil {
+		b.reply(ctx, "failed to read container logs: "+err.Error())
+		return
+	}
+
+	text = dockerctl.Redact(text, b.secrets())
+	text = strings.TrimRight(text, "\n")
+	if text == "" {
+		b.reply(ctx, "container log is empty")
+		return
+	}
+	if len(text) > logsUploadMaxBytes {
+		text = text[len(text)-logsUploadMaxBytes:]
+		// Drop the partial first line, which may also be a split rune.
+		if i := strings.IndexByte(text, '\n'); i >= 0 {
+			text = text[i+1:]
+		}
+		text = "[truncated]\n" + text
+	}
+
+	if len(text) <= logsInlineMaxBytes && strings.Count(text, "\n") < logsInlineMaxLines {
+		b.replyHTML(ctx, "```\n"+text+"\n```", "<pre><code>"+html.EscapeString(text)+"</code></pre>")
+		return
+	}
+	b.replyFile(ctx, fmt.Sprintf("%s-%s.log", b.cfg.DockerContainerName, time.Now().UTC().Format("20060102-150405")), []byte(text+"\n"))
+}
+
+// secrets lists credentials the bot itself knows about, on top of what
+// dockerctl finds in the container environment.
+func (b *Bot) secrets() []string {
+	return []string{
+		b.cfg.RCONPass,
+		b.cfg.RESTAPIPass,
+		b.cfg.MatrixPassword,
+		b.cfg.MatrixAccessToken,
+		b.matrix.AccessToken,
+	}
+}
+
+func (b *Bot) replyFile(ctx context.Context, name string, data []byte) {
+	upload, err := b.matrix.UploadBytesWithName(ctx, data, "text/plain", name)
+	if err != nil {
+		b.reply(ctx, "failed to upload file: "+err.Error())
+		return
+	}
+	content := &event.MessageEventContent{
+		MsgType:  event.MsgFile,
+		Body:     name,
+		FileName: name,
+		URL:      upload.ContentURI.CUString(),
+		Info: &event.FileInfo{
+			MimeType: "text/plain",
+			Size:     len(data),
+		},
+	}
+	if _, err := b.matrix.SendMessageEvent(ctx, b.roomID, event.EventMessage, content); err != nil {
+		b.log.Error("failed sending matrix message", "err", err.Error())
+	}
+}
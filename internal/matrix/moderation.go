@@ -0,0 +1,138 @@
+package matrix
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"pikabot/internal/game"
+	"pikabot/internal/moderation"
+
+	"maunium.net/go/mautrix/id"
+)
+
+func (b *Bot) handleKick(ctx context.Context, sender id.UserID, args []string) {
+	if len(args) == 0 {
+		b.reply(ctx, "usage: kick <player>")
+		return
+	}
+	player, _, ok := b.resolvePlayer(ctx, []string{strings.Join(args, " ")}, false)
+	if !ok {
+		return
+	}
+
+	kickCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	err := b.game.KickPlayer(kickCtx, player.SteamID)
+	cancel()
+	b.audit(ctx, moderation.Entry{Actor: sender.String(), Action: moderation.Kick, SteamID: player.SteamID, Name: player.Name}, err)
+	if err != nil {
+		b.reply(ctx, "failed to kick "+describePlayer(player)+": "+err.Error())
+		return
+	}
+	b.reply(ctx, "kicked "+describePlayer(player))
+}
+
+func (b *Bot) handleBan(ctx context.Context, sender id.UserID, args []string) {
+	if len(args) == 0 {
+		b.reply(ctx, "usage: ban <player> [reason]")
+		return
+	}
+	// Banning someone who already left is common, so a full Steam ID is
+	// accepted even when that player is not online. The words after the
+	// longest name that matches a player are the reason.
+	player, rest, ok := b.resolvePlayer(ctx, args, true)
+	if !ok {
+		return
+	}
+	reason := strings.Join(rest, " ")
+
+	banCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	err := b.game.BanPlayer(banCtx, player.SteamID)
+	cancel()
+	b.audit(ctx, moderation.Entry{Actor: sender.String(), Action: moderation.Ban, SteamID: player.SteamID, Name: player.Name, Reason: reason}, err)
+	if err != nil {
+		b.reply(ctx, "failed to ban "+describePlayer(player)+": "+err.Error())
+		return
+	}
+	msg := "banned " + describePlayer(player)
+	if reason != "" {
+		msg += ": " + reason
+	}
+	b.reply(ctx, msg)
+}
+
+func (b *Bot) handleUnban(ctx context.Context, sender id.UserID, args []string) {
+	if len(args) != 1 || !moderation.LooksLikeSteamID(args[0]) {
+		b.reply(ctx, "usage: unban <steamid>")
+		return
+	}
+	steamID := moderation.CanonicalSteamID(args[0])
+
+	unbanCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	err := b.game.UnBanPlayer(unbanCtx, steamID)
+	cancel()
+	b.audit(ctx, moderation.Entry{Actor: sender.String(), Action: moderation.Unban, SteamID: steamID}, err)
+	if err != nil {
+		b.reply(ctx, "failed to unban "+steamID+": "+err.Error())
+		return
+	}
+	b.reply(ctx, "unbanned "+steamID)
+}
+
+// resolvePlayer looks the leading words up in the live player list and
+// explains to the room why they could not be resolved. It returns the words
+// after the player's name. With allowOffline, a well-formed Steam ID as the
+// first word that is not online is returned as well. The Steam ID is always
+// in its canonical steam_ form.
+func (b *Bot) resolvePlayer(ctx context.Context, words []string, allowOffline bool) (game.Player, []string, bool) {
+	offline := allowOffline && moderation.LooksLikeSteamID(words[0])
+	players, err := b.onlinePlayers(ctx)
+	if err != nil {
+		if offline {
+			return game.Player{SteamID: moderation.CanonicalSteamID(words[0])}, words[1:], true
+		}
+		b.reply(ctx, "could not read the player list from the game server: "+err.Error())
+		return game.Player{}, nil, false
+	}
+
+	player, rest, err := moderation.ResolveLeading(players, words)
+	var notFound *moderation.NotFoundError
+	switch {
+	case err == nil:
+		// A player who is still connecting has no usable ID; acting on the
+		// placeholder would report a kick or ban that never happened.
+		if !moderation.LooksLikeSteamID(player.SteamID) {
+			b.reply(ctx, player.Name+" has no valid Steam ID yet (still connecting?); try again")
+			return game.Player{}, nil, false
+		}
+		// ShowPlayers may print bare SteamID64s; kicks, bans and the audit
+		// log always use the steam_ form.
+		player.SteamID = moderation.CanonicalSteamID(player.SteamID)
+		return player, rest, true
+	case errors.As(err, &notFound) && offline:
+		return game.Player{SteamID: moderation.CanonicalSteamID(words[0])}, words[1:], true
+	default:
+		b.reply(ctx, err.Error())
+		return game.Player{}, nil, false
+	}
+}
+
+// audit records a moderation attempt, including failed ones. A broken audit
+// log is reported but does not undo the action.
+func (b *Bot) audit(ctx context.Context, entry moderation.Entry, actionErr error) {
+	if actionErr != nil {
+		entry.Error = actionErr.Error()
+	}
+	if err := b.auditLog.Record(entry); err != nil {
+		b.log.Error("failed writing audit log", "err", err.Error())
+		b.reply(ctx, "warning: could not write the audit log: "+err.Error())
+	}
+}
+
+func describePlayer(p game.Player) string {
+	if p.Name == "" {
+		return p.SteamID
+	}
+	return p.Name + " (" + p.SteamID + ")"
+}
@@ -0,0 +1,111 @@
+package matrix
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"pikabot/internal/game"
+	"pikabot/internal/moderation"
+)
+
+func TestModerationUsesCanonicalSteamIDs(t *testing.T) {
+	const canonical = "steam_76561198000000003"
+	g := &fakeGame{players: []game.Player{{Name: "bob", PlayerUID: "778899", SteamID: "76561198000000003"}}}
+	b, _ := newTestBot(t, g)
+	ctx := context.Background()
+
+	b.handleKick(ctx, "@admin:example.org", []string{"bob"})
+	b.handleBan(ctx, "@admin:example.org", []string{"bob", "griefing"})
+	b.handleBan(ctx, "@admin:example.org", []string{"76561198000000009", "alt", "account"})
+	b.handleUnban(ctx, "@admin:example.org", []string{"76561198000000009"})
+
+	if want := []string{canonical}; !reflect.DeepEqual(g.kicked, want) {
+		t.Fatalf("kicked %v want %v", g.kicked, want)
+	}
+	if want := []string{canonical, "steam_76561198000000009"}; !reflect.DeepEqual(g.banned, want) {
+		t.Fatalf("banned %v want %v", g.banned, want)
+	}
+	if want := []string{"steam_76561198000000009"}; !reflect.DeepEqual(g.unbanned, want) {
+		t.Fatalf("unbanned %v want %v", g.unbanned, want)
+	}
+
+	want := []string{canonical, canonical, "steam_76561198000000009", "steam_76561198000000009"}
+	if got := auditedSteamIDs(t, filepath.Join(b.cfg.DataDir, "audit.jsonl")); !reflect.DeepEqual(got, want) {
+		t.Fatalf("audit log Steam IDs %v want %v", got, want)
+	}
+}
+
+func TestModerationRejectsPlayersWithoutSteamID(t *testing.T) {
+	g := &fakeGame{players: []game.Player{
+		{Name: "Alice", PlayerUID: "112233"},
+		{Name: "Newcomer", PlayerUID: "445566", SteamID: "steam_00000000000000000"},
+	}}
+	b, room := newTestBot(t, g)
+	ctx := context.Background()
+
+	b.handleKick(ctx, "@admin:example.org", []string{"Alice"})
+	b.handleBan(ctx, "@admin:example.org", []string{"Newcomer", "griefing"})
+
+	if len(g.kicked) != 0 || len(g.banned) != 0 {
+		t.Fatalf("kicked %v banned %v, want none", g.kicked, g.banned)
+	}
+	if _, err := os.Stat(filepath.Join(b.cfg.DataDir, "audit.jsonl")); !os.IsNotExist(err) {
+		t.Fatalf("audit log written for a player without a Steam ID: %v", err)
+	}
+	want := []string{
+		"Alice has no valid Steam ID yet (still connecting?); try again",
+		"Newcomer has no valid Steam ID yet (still connecting?); try again",
+	}
+	if got := room.sent(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("room messages %q want %q", got, want)
+	}
+}
+
+func auditedSteamIDs(t *testing.T, path string) []string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open audit log: %v", err)
+	}
+	defer f.Close()
+
+	var ids []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry moderation.Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("decode audit entry %q: %v", scanner.Text(), err)
+		}
+		ids = append(ids, entry.SteamID)
+	}
+	return ids
+}
+
+func TestModerationResolvesNamesWithSpaces(t *testing.T) {
+	bigBob := game.Player{Name: "Big Bob", SteamID: "steam_76561198000000004"}
+	bigBen := game.Player{Name: "Big Ben", SteamID: "steam_76561198000000005"}
+	g := &fakeGame{players: []game.Player{bigBob, bigBen}}
+	b, room := newTestBot(t, g)
+	ctx := context.Background()
+
+	b.handleKick(ctx, "@admin:example.org", []string{"Big", "Bob"})
+	b.handleBan(ctx, "@admin:example.org", []string{"big", "ben", "griefing", "bases"})
+	b.handleKick(ctx, "@admin:example.org", []string{"Big"})
+
+	if want := []string{bigBob.SteamID}; !reflect.DeepEqual(g.kicked, want) {
+		t.Fatalf("kicked %v want %v", g.kicked, want)
+	}
+	if want := []string{bigBen.SteamID}; !reflect.DeepEqual(g.banned, want) {
+		t.Fatalf("banned %v want %v", g.banned, want)
+	}
+	sent := room.sent()
+	if len(sent) != 3 || sent[1] != "banned Big Ben ("+bigBen.SteamID+"): griefing bases" || !strings.Contains(sent[2], "matches several players") {
+		t.Fatalf("room messages %q", sent)
+	}
+}
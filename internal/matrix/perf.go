@@ -0,0 +1,105 @@
+package matrix
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"pikabot/internal/palapi"
+)
+
+func (b *Bot) handlePerf(ctx context.Context) {
+	metrics, err := b.serverMetrics(ctx)
+	if err != nil {
+		b.reply(ctx, "failed to read server metrics from the REST API (is RESTAPIEnabled set?): "+err.Error())
+		return
+	}
+
+	summary := &summary{title: "Palworld performance"}
+	summary.add("FPS", fmt.Sprintf("%d", metrics.ServerFPS))
+	summary.add("Frame time", fmt.Sprintf("%.1f ms", metrics.ServerFrameTime))
+	summary.add("Players", fmt.Sprintf("%d / %d", metrics.CurrentPlayers, metrics.MaxPlayers))
+	summary.add("In-game day", fmt.Sprintf("%d", metrics.Days))
+	summary.add("Uptime", formatUptime(metrics.Uptime()))
+	b.replyHTML(ctx, summary.plain(), summary.html())
+}
+
+func (b *Bot) serverMetrics(ctx context.Context) (palapi.Metrics, error) {
+	metricsCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	return b.metrics.Metrics(metricsCtx)
+}
+
+// fpsAlarm fires once when FPS has stayed below the threshold for the whole
+// window, and reports recovery once it is back at or above it.
+type fpsAlarm struct {
+	threshold int
+	window    time.Duration
+	lowSince  time.Time
+	fired     bool
+}
+
+func (a *fpsAlarm) observe(now time.Time, fps int) (fired, recovered bool) {
+	if fps >= a.threshold {
+		recovered = a.fired
+		a.reset()
+		return false, recovered
+	}
+	if a.lowSince.IsZero() {
+		a.lowSince = now
+	}
+	if !a.fired && now.Sub(a.lowSince) >= a.window {
+		a.fired = true
+		return true, false
+	}
+	return false, false
+}
+
+// missed restarts the low-FPS streak after a failed poll, since it is
+// unknown whether FPS stayed low in between. A warning that already fired
+// stays active so a flaky API does not repeat it.
+func (a *fpsAlarm) missed() {
+	a.lowSince = time.Time{}
+}
+
+// reset forgets the current streak and any fired warning, e.g. when the
+// container stopped.
+func (a *fpsAlarm) reset() {
+	a.lowSince = time.Time{}
+	a.fired = false
+}
+
+func (b *Bot) watchPerf(ctx context.Context) {
+	alarm := &fpsAlarm{threshold: b.cfg.FPSWarnThreshold, window: b.cfg.FPSWarnAfter}
+	ticker := time.NewTicker(b.cfg.PerfPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		status, err := b.docker.Status(ctx)
+		if err != nil || !status.Exists || !status.Running {
+			alarm.reset()
+			continue
+		}
+		metrics, err := b.serverMetrics(ctx)
+		if err != nil {
+			b.log.Debug("fps check: metrics unavailable", "err", err.Error())
+			alarm.missed()
+			continue
+		}
+
+		fired, recovered := alarm.observe(time.Now(), metrics.ServerFPS)
+		switch {
+		case fired:
+			b.reply(ctx, fmt.Sprintf("warning: server FPS has been below %d for %s (now %d FPS, %.1f ms frame time, %d players online)",
+				b.cfg.FPSWarnThreshold, formatDuration(b.cfg.FPSWarnAfter), metrics.ServerFPS, metrics.ServerFrameTime, metrics.CurrentPlayers))
+		case recovered:
+			b.reply(ctx, fmt.Sprintf("server FPS recovered (%d FPS)", metrics.ServerFPS))
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package matrix
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFPSAlarm(t *testing.T) {
+	start := time.Date(2024, 3, 1, 20, 0, 0, 0, time.UTC)
+	alarm := &fpsAlarm{threshold: 20, window: 5 * time.Minute}
+	steps := []struct {
+		at            time.Duration
+		fps           int
+		missed        bool
+		wantFired     bool
+		wantRecovered bool
+	}{
+		{at: 0, fps: 60},
+		{at: time.Minute, fps: 15},
+		{at: 4 * time.Minute, fps: 12},
+		{at: 5 * time.Minute, fps: 25},
+		{at: 6 * time.Minute, fps: 10},
+		{at: 10 * time.Minute, fps: 18},
+		{at: 11 * time.Minute, fps: 19, wantFired: true},
+		{at: 12 * time.Minute, fps: 5},
+		{at: 13 * time.Minute, fps: 20, wantRecovered: true},
+		{at: 14 * time.Minute, fps: 60},
+		{at: 15 * time.Minute, fps: 10},
+		{at: 18 * time.Minute, missed: true},
+		{at: 20 * time.Minute, fps: 10},
+		{at: 24 * time.Minute, fps: 10},
+		{at: 25 * time.Minute, fps: 10, wantFired: true},
+		{at: 26 * time.Minute, missed: true},
+		{at: 27 * time.Minute, fps: 8},
+		{at: 33 * time.Minute, fps: 8},
+		{at: 34 * time.Minute, fps: 30, wantRecovered: true},
+	}
+	for i, step := range steps {
+		if step.missed {
+			alarm.missed()
+			continue
+		}
+		fired, recovered := alarm.observe(start.Add(step.at), step.fps)
+		if fired != step.wantFired || recovered != step.wantRecovered {
+			t.Fatalf("step %d: observe(%d) got fired=%v recovered=%v want fired=%v recovered=%v",
+				i, step.fps, fired, recovered, step.wantFired, step.wantRecovered)
+		}
+	}
+}
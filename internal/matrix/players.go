@@ -0,0 +1,48 @@
+package matrix
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+func (b *Bot) handlePlayers(ctx context.Context) {
+	status, err := b.docker.Status(ctx)
+	if err != nil {
+		b.reply(ctx, "error checking server status: "+err.Error())
+		return
+	}
+	if !status.Exists || !status.Running {
+		b.reply(ctx, "server is not running")
+		return
+	}
+
+	players, err := b.onlinePlayers(ctx)
+	if err != nil {
+		b.reply(ctx, "could not list players from the game server")
+		b.log.Warn("players: player check failed", "err", err.Error())
+		return
+	}
+	if len(players) == 0 {
+		b.reply(ctx, "nobody is online")
+		return
+	}
+
+	lines := []string{fmt.Sprintf("Online players (%d):", len(players))}
+	for _, session := range b.presence.sessions(time.Now(), players) {
+		lines = append(lines, "- "+session.player.Name+": "+formatSession(session))
+	}
+	b.reply(ctx, strings.Join(lines, "\n"))
+}
+
+func formatSession(s playerSession) string {
+	switch {
+	case s.unknown:
+		return "just joined"
+	case s.atLeast:
+		return "at least " + formatUptime(s.online)
+	default:
+		return formatUptime(s.online)
+	}
+}
@@ -0,0 +1,104 @@
+package matrix
+
+import (
+	"context"
+	"time"
+
+	"pikabot/internal/restartpolicy"
+)
+
+func (b *Bot) restartPolicy() restartpolicy.Policy {
+	return restartpolicy.Policy{
+		MaxUptime:     b.cfg.RestartMaxUptime,
+		MemoryPercent: float64(b.cfg.RestartMemoryPercent),
+		Countdown:     b.cfg.RestartCountdown,
+	}
+}
+
+func (b *Bot) watchRestartPolicy(ctx context.Context) {
+	policy := b.restartPolicy()
+	engine := restartpolicy.New(policy, time.Now)
+	ticker := time.NewTicker(b.cfg.RestartCheckInterval)
+	defer ticker.Stop()
+
+	b.log.Info("restart policy started", "max_uptime", policy.MaxUptime.String(), "memory_percent", policy.MemoryPercent, "countdown", policy.Countdown.String())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if b.stopPending() {
+			continue
+		}
+
+		obs, ok := b.observeForPolicy(ctx, policy)
+		if !ok {
+			continue
+		}
+		decision := engine.Evaluate(obs)
+		switch decision.Action {
+		case restartpolicy.RestartNow:
+			b.policyRestart(ctx, decision.Reason)
+		case restartpolicy.Countdown:
+			if b.startCountdown(ctx, decision.Delay, true, engine.Cancel) == nil {
+				b.reply(ctx, "scheduled restart: "+decision.Reason+"; players are online, restarting in "+formatDuration(decision.Delay)+" (cancel with "+b.cfg.CommandPrefix+"cancelstop)")
+			}
+		}
+	}
+}
+
+func (b *Bot) observeForPolicy(ctx context.Context, policy restartpolicy.Policy) (restartpolicy.Observation, bool) {
+	status, err := b.docker.Status(ctx)
+	if err != nil {
+		b.log.Warn("restart policy: docker status failed", "err", err.Error())
+		return restartpolicy.Observation{}, false
+	}
+	obs := restartpolicy.Observation{Running: status.Exists && status.Running, StartedAt: status.StartedAt}
+	if !obs.Running {
+		return obs, true
+	}
+
+	if policy.MemoryPercent > 0 {
+		stats, err := b.containerStats(ctx)
+		if err != nil {
+			b.log.Warn("restart policy: docker stats failed", "err", err.Error())
+			return restartpolicy.Observation{}, false
+		}
+		obs.MemoryPercent = stats.MemoryPercent
+	}
+
+	players, err := b.onlinePlayers(ctx)
+	if err == nil {
+		obs.PlayersKnown = true
+		obs.Players = len(players)
+	}
+	return obs, true
+}
+
+// policyRestart runs an empty-server restart like !restartpal does. A busy
+// bot skips it; the engine will trigger again after its cooldown.
+func (b *Bot) policyRestart(ctx context.Context, reason string) {
+	if !b.busy.CompareAndSwap(false, true) {
+		b.log.Info("restart policy: bot is busy, skipping restart", "reason", reason)
+		return
+	}
+	defer b.busy.Store(false)
+
+	players, err := b.onlinePlayers(ctx)
+	if err != nil {
+		b.log.Warn("restart policy: could not confirm zero players", "reason", reason, "err", err.Error())
+		b.reply(ctx, "scheduled restart skipped: "+reason+"; could not confirm zero players: "+err.Error())
+		return
+	}
+	if len(players) > 0 {
+		b.log.Info("restart policy: players joined, skipping restart", "reason", reason, "players", len(players))
+		return
+	}
+
+	b.reply(ctx, "scheduled restart: "+reason+"; nobody is online, saving and restarting")
+	if !b.saveBeforeStop(ctx, "restart") {
+		return
+	}
+	b.restartContainer(ctx)
+}
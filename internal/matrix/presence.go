@@ -0,0 +1,206 @@
+package matrix
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"pikabot/internal/game"
+)
+
+type onlinePlayer struct {
+	player    game.Player
+	firstSeen time.Time
+	// primed marks players already online when tracking began, whose real
+	// join time is earlier than firstSeen.
+	primed bool
+}
+
+type playerSession struct {
+	player  game.Player
+	online  time.Duration
+	atLeast bool
+	unknown bool
+}
+
+type presence struct {
+	mu     sync.Mutex
+	primed bool
+	online map[string]onlinePlayer
+}
+
+func newPresence() *presence {
+	return &presence{online: make(map[string]onlinePlayer)}
+}
+
+// playerKey identifies a player across polls. Names are not unique, so the
+// UID is preferred and the name only used when the server omits it.
+func playerKey(p game.Player) string {
+	if p.PlayerUID != "" {
+		return "uid:" + p.PlayerUID
+	}
+	return "name:" + p.Name
+}
+
+// update diffs players against the previous poll. The first poll after a
+// reset only primes the state, so a bot or server restart does not announce
+// everyone as having just joined.
+func (p *presence) update(now time.Time, players []game.Player) (joined, left []game.Player) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	current := make(map[string]game.Player, len(players))
+	for _, player := range players {
+		current[playerKey(player)] = player
+	}
+
+	for key, entry := range p.online {
+		if _, ok := current[key]; !ok {
+			delete(p.online, key)
+			left = append(left, entry.player)
+		}
+	}
+	for key, player := range current {
+		if entry, ok := p.online[key]; ok {
+			entry.player = player
+			p.online[key] = entry
+			continue
+		}
+		p.online[key] = onlinePlayer{player: player, firstSeen: now, primed: !p.primed}
+		joined = append(joined, player)
+	}
+
+	if !p.primed {
+		p.primed = true
+		return nil, nil
+	}
+	sortPlayers(joined)
+	sortPlayers(left)
+	return joined, left
+}
+
+// sessions pairs a live player list with tracked first-seen times. Players
+// the poller has not seen yet are reported as unknown.
+func (p *presence) sessions(now time.Time, players []game.Player) []playerSession {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	out := make([]playerSession, 0, len(players))
+	for _, player := range players {
+		entry, ok := p.online[playerKey(player)]
+		if !ok {
+			out = append(out, playerSession{player: player, unknown: true})
+			continue
+		}
+		out = append(out, playerSession{
+			player:  player,
+			online:  now.Sub(entry.firstSeen),
+			atLeast: entry.primed,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].player.Name < out[j].player.Name
+	})
+	return out
+}
+
+func (p *presence) reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.primed = false
+	p.online = make(map[string]onlinePlayer)
+}
+
+func sortPlayers(players []game.Player) {
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].Name < players[j].Name
+	})
+}
+
+func (b *Bot) watchPresence(ctx context.Context) {
+	ticker := time.NewTicker(b.cfg.PresencePollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.checkPresence(ctx)
+		}
+	}
+}
+
+func (b *Bot) checkPresence(ctx context.Context) {
+	status, err := b.docker.Status(ctx)
+	if err != nil {
+		b.log.Warn("presence check: docker status failed", "err", err.Error())
+		return
+	}
+	if !status.Exists || !status.Running {
+		b.presence.reset()
+		return
+	}
+
+	players, err := b.onlinePlayers(ctx)
+	if err != nil {
+		// Keep the previous state: a flaky player poll must not look like
+		// everyone leaving and rejoining.
+		b.log.Debug("presence check: player check failed", "err", err.Error())
+		return
+	}
+
+	joined, left := b.presence.update(time.Now(), players)
+	if len(joined) == 0 && len(left) == 0 {
+		return
+	}
+	if !b.settings.Room(b.roomID).AnnouncePresence {
+		return
+	}
+
+	lines := make([]string, 0, len(joined)+len(left))
+	for _, player := range joined {
+		lines = append(lines, player.Name+" joined")
+	}
+	for _, player := range left {
+		lines = append(lines, player.Name+" left")
+	}
+	b.reply(ctx, strings.Join(lines, "\n"))
+}
+
+func (b *Bot) handleAnnounce(ctx context.Context, args []string) {
+	if len(args) == 0 {
+		state := "off"
+		if b.settings.Room(b.roomID).AnnouncePresence {
+			state = "on"
+		}
+		b.reply(ctx, "join/leave announcements are "+state)
+		return
+	}
+
+	var enabled bool
+	switch strings.ToLower(args[0]) {
+	case "on":
+		enabled = true
+	case "off":
+		enabled = false
+	default:
+		b.reply(ctx, "usage: announce [on|off]")
+		return
+	}
+
+	err := b.settings.Update(b.roomID, func(s *RoomSettings) {
+		s.AnnouncePresence = enabled
+	})
+	if err != nil {
+		b.reply(ctx, "failed to save setting: "+err.Error())
+		return
+	}
+	if enabled {
+		b.reply(ctx, "join/leave announcements enabled")
+	} else {
+		b.reply(ctx, "join/leave announcements disabled")
+	}
+}
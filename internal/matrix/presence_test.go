@@ -0,0 +1,120 @@
+package matrix
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"pikabot/internal/game"
+	"pikabot/internal/rcon"
+)
+
+func TestPresenceUpdate(t *testing.T) {
+	const header = "name,playeruid,steamid\n"
+	tests := []struct {
+		name       string
+		polls      []string
+		wantJoined [][]string
+		wantLeft   [][]string
+	}{
+		{
+			name:       "first poll only primes",
+			polls:      []string{header + "Alice,uid1,steam1\nBob,uid2,steam2\n"},
+			wantJoined: [][]string{nil},
+			wantLeft:   [][]string{nil},
+		},
+		{
+			name: "join and leave",
+			polls: []string{
+				header + "Alice,uid1,steam1\n",
+				header + "Alice,uid1,steam1\nBob,uid2,steam2\n",
+				header + "Bob,uid2,steam2\n",
+				header,
+			},
+			wantJoined: [][]string{nil, {"Bob"}, nil, nil},
+			wantLeft:   [][]string{nil, nil, {"Alice"}, {"Bob"}},
+		},
+		{
+			name: "simultaneous changes sorted by name",
+			polls: []string{
+				header + "Zed,uid9,steam9\nYan,uid8,steam8\n",
+				header + "Carol,uid3,steam3\nAlice,uid1,steam1\n",
+			},
+			wantJoined: [][]string{nil, {"Alice", "Carol"}},
+			wantLeft:   [][]string{nil, {"Yan", "Zed"}},
+		},
+		{
+			name: "same name different uid is a different player",
+			polls: []string{
+				header + "Pal,uid1,steam1\n",
+				header + "Pal,uid2,steam2\n",
+			},
+			wantJoined: [][]string{nil, {"Pal"}},
+			wantLeft:   [][]string{nil, {"Pal"}},
+		},
+		{
+			name: "rename keeps uid and is not a join",
+			polls: []string{
+				header + "Alice,uid1,steam1\n",
+				header + "Alicia,uid1,steam1\n",
+			},
+			wantJoined: [][]string{nil, nil},
+			wantLeft:   [][]string{nil, nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPresence()
+			now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+			for i, poll := range tt.polls {
+				joined, left := p.update(now.Add(time.Duration(i)*time.Minute), rcon.ParseShowPlayers(poll))
+				if got := names(joined); !reflect.DeepEqual(got, tt.wantJoined[i]) {
+					t.Fatalf("poll %d: joined got %v want %v", i, got, tt.wantJoined[i])
+				}
+				if got := names(left); !reflect.DeepEqual(got, tt.wantLeft[i]) {
+					t.Fatalf("poll %d: left got %v want %v", i, got, tt.wantLeft[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPresenceResetPrimesAgain(t *testing.T) {
+	p := newPresence()
+	now := time.Now()
+	p.update(now, rcon.ParseShowPlayers("name,playeruid,steamid\nAlice,uid1,steam1\n"))
+	p.reset()
+
+	joined, left := p.update(now, rcon.ParseShowPlayers("name,playeruid,steamid\nBob,uid2,steam2\n"))
+	if joined != nil || left != nil {
+		t.Fatalf("update() after reset got joined %v left %v, want none", joined, left)
+	}
+}
+
+func names(players []game.Player) []string {
+	if len(players) == 0 {
+		return nil
+	}
+	return game.Names(players)
+}
+
+func TestPresenceSessions(t *testing.T) {
+	const header = "name,playeruid,steamid\n"
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	p := newPresence()
+	p.update(start, rcon.ParseShowPlayers(header+"Alice,uid1,steam1\n"))
+	p.update(start.Add(10*time.Minute), rcon.ParseShowPlayers(header+"Alice,uid1,steam1\nBob,uid2,steam2\n"))
+
+	live := rcon.ParseShowPlayers(header + "Carol,uid3,steam3\nBob,uid2,steam2\nAlice,uid1,steam1\n")
+	got := p.sessions(start.Add(time.Hour), live)
+	want := []playerSession{
+		{player: live[2], online: time.Hour, atLeast: true},
+		{player: live[1], online: 50 * time.Minute},
+		{player: live[0], unknown: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("sessions() got %+v want %+v", got, want)
+	}
+}
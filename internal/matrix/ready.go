@@ -0,0 +1,93 @@
+package matrix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"pikabot/internal/dockerctl"
+)
+
+const readyPollInterval = 5 * time.Second
+
+var errNotReady = errors.New("server did not become ready in time")
+
+// waitReady polls until the game server answers and, if the image defines a
+// HEALTHCHECK, Docker reports the container healthy. A container that stops
+// while we wait is reported right away instead of running into the timeout.
+func (b *Bot) waitReady(ctx context.Context, timeout time.Duration) (time.Duration, error) {
+	started := time.Now()
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(readyPollInterval)
+	defer ticker.Stop()
+	lastHealth := ""
+	for {
+		select {
+		case <-waitCtx.Done():
+			if ctx.Err() != nil {
+				return time.Since(started), ctx.Err()
+			}
+			if lastHealth != "" {
+				return time.Since(started), fmt.Errorf("%w (health: %s)", errNotReady, lastHealth)
+			}
+			return time.Since(started), errNotReady
+		case <-ticker.C:
+		}
+
+		status, err := b.docker.Status(waitCtx)
+		if err != nil {
+			b.log.Debug("ready check: docker status failed", "err", err.Error())
+			continue
+		}
+		if !status.Exists || !status.Running {
+			return time.Since(started), crashError(status)
+		}
+		lastHealth = status.Health
+		if status.Health != "" && status.Health != "healthy" {
+			continue
+		}
+
+		infoCtx, cancelInfo := context.WithTimeout(waitCtx, 5*time.Second)
+		_, err = b.game.Info(infoCtx)
+		cancelInfo()
+		if err == nil {
+			return time.Since(started), nil
+		}
+		b.log.Debug("ready check: game server not answering yet", "err", err.Error())
+	}
+}
+
+// reportReady runs waitReady in the background, since booting takes minutes
+// and the sync loop must keep handling other commands, and posts the result.
+func (b *Bot) reportReady(ctx context.Context, upMessage, action string) {
+	b.goTask(func() {
+		took, err := b.waitReady(ctx, b.cfg.ReadyTimeout)
+		switch {
+		case err == nil:
+			b.reply(ctx, upMessage+" (took "+formatSeconds(took)+")")
+		case errors.Is(err, errNotReady):
+			b.reply(ctx, "server did not answer within "+formatDuration(b.cfg.ReadyTimeout)+" after "+action+"; check the container ("+err.Error()+")")
+		case ctx.Err() != nil:
+		default:
+			b.reply(ctx, "server failed during "+action+": "+err.Error())
+		}
+	})
+}
+
+func crashError(status dockerctl.Status) error {
+	if !status.Exists {
+		return errors.New("container disappeared while booting")
+	}
+	msg := fmt.Sprintf("container stopped while booting (state %s, exit code %d", status.State, status.ExitCode)
+	if status.OOMKilled {
+		msg += ", OOM killed"
+	}
+	return errors.New(msg + ")")
+}
+
+func formatSeconds(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
@@ -0,0 +1,48 @@
+package matrix
+
+import (
+	"context"
+	"time"
+)
+
+func (b *Bot) handleRestart(ctx context.Context) {
+	status, err := b.docker.Status(ctx)
+	if err != nil {
+		b.reply(ctx, "error checking server status: "+err.Error())
+		return
+	}
+	if !status.Exists {
+		b.reply(ctx, "configured container was not found")
+		return
+	}
+	if !status.Running {
+		b.reply(ctx, "server is not running; use "+b.cfg.CommandPrefix+"startpal")
+		return
+	}
+
+	if !b.confirmNoPlayers(ctx, "restart") {
+		return
+	}
+
+	if !b.saveBeforeStop(ctx, "restart") {
+		return
+	}
+
+	b.supersedeCountdown(ctx)
+	b.restartContainer(ctx)
+}
+
+// restartContainer restarts and follows up in the background once the game
+// answers again.
+func (b *Bot) restartContainer(ctx context.Context) {
+	restartCtx, cancelRestart := context.WithTimeout(ctx, 60*time.Second)
+	err := b.docker.Restart(restartCtx, 30*time.Second)
+	cancelRestart()
+	if err != nil {
+		b.reply(ctx, "failed to restart server: "+err.Error())
+		return
+	}
+
+	b.reply(ctx, "restarting Palworld server...")
+	b.reportReady(ctx, "server is back up", "restart")
+}
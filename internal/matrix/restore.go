@@ -0,0 +1,208 @@
+package matrix
+
+import (
+	"context"
+	"time"
+
+	"pikabot/internal/backup"
+
+	"maunium.net/go/mautrix/id"
+)
+
+const restoreConfirmWindow = 2 * time.Minute
+
+type pendingRestore struct {
+	archive backup.Archive
+	sender  id.UserID
+	expires time.Time
+}
+
+// handleRestore arms a restore with "restore <id>" and runs it only after
+// the same sender answers "restore confirm" within restoreConfirmWindow.
+func (b *Bot) handleRestore(ctx context.Context, sender id.UserID, args []string) {
+	usage := "usage: restore <backup-id> | restore confirm | restore cancel"
+	if len(args) != 1 {
+		b.reply(ctx, usage)
+		return
+	}
+
+	switch args[0] {
+	case "confirm":
+		if b.restoreFor(sender, false) == nil {
+			b.reply(ctx, "no restore waiting for your confirmation")
+			return
+		}
+		// Refusals leave the restore armed so the user can simply retry.
+		if b.stopPending() {
+			b.reply(ctx, "refused to restore: a countdown is pending; use cancelstop first")
+			return
+		}
+		if !b.busy.CompareAndSwap(false, true) {
+			b.reply(ctx, "busy, try again")
+			return
+		}
+		pending := b.restoreFor(sender, true)
+		if pending == nil {
+			b.busy.Store(false)
+			b.reply(ctx, "no restore waiting for your confirmation")
+			return
+		}
+		// The restore waits minutes for the server to boot, so it runs in
+		// the background and releases the command lock when it is done.
+		b.goTask(func() {
+			defer b.busy.Store(false)
+			b.runRestore(ctx, pending.archive)
+		})
+	case "cancel":
+		b.mu.Lock()
+		pending := b.pendingRestore
+		b.pendingRestore = nil
+		b.mu.Unlock()
+		if pending == nil {
+			b.reply(ctx, "no restore pending")
+			return
+		}
+		b.reply(ctx, "restore of backup "+pending.archive.ID+" cancelled")
+	default:
+		archive, err := b.backups.Get(args[0])
+		if err != nil {
+			b.reply(ctx, err.Error()+" (see "+b.cfg.CommandPrefix+"backups)")
+			return
+		}
+		b.mu.Lock()
+		b.pendingRestore = &pendingRestore{archive: archive, sender: sender, expires: time.Now().Add(restoreConfirmWindow)}
+		b.mu.Unlock()
+		b.reply(ctx, "this stops the server and replaces the current save with backup "+archive.ID+
+			" (created "+archive.Created.Local().Format("Mon 2006-01-02 15:04")+"). "+
+			"Reply "+b.cfg.CommandPrefix+"restore confirm within "+formatDuration(restoreConfirmWindow)+" to proceed.")
+	}
+}
+
+// restoreFor returns the unexpired restore armed by sender, if any. With
+// take, it is also removed so it runs only once.
+func (b *Bot) restoreFor(sender id.UserID, take bool) *pendingRestore {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	pending := b.pendingRestore
+	if pending == nil || pending.sender != sender || !time.Now().Before(pending.expires) {
+		return nil
+	}
+	if take {
+		b.pendingRestore = nil
+	}
+	return pending
+}
+
+func (b *Bot) runRestore(ctx context.Context, archive backup.Archive) {
+	b.cancelAutoRestart(ctx)
+	saveDir, err := b.saveDir(ctx)
+	if err != nil {
+		b.reply(ctx, "restore failed: "+err.Error())
+		return
+	}
+	status, err := b.docker.Status(ctx)
+	if err != nil {
+		b.reply(ctx, "error checking server status: "+err.Error())
+		return
+	}
+	if !status.Exists {
+		b.reply(ctx, "configured container was not found")
+		return
+	}
+
+	if status.Running {
+		if !b.confirmNoPlayers(ctx, "restore") {
+			return
+		}
+		// Saving first makes the safety snapshot as fresh as possible.
+		if !b.saveBeforeStop(ctx, "restore") {
+			return
+		}
+		if err := b.stopContainer(ctx); err != nil {
+			b.reply(ctx, "restore aborted: failed to stop server: "+err.Error())
+			return
+		}
+	}
+
+	b.reply(ctx, "restoring backup "+archive.ID+"...")
+	restored, err := b.backups.Replace(ctx, archive, saveDir)
+	snapshot := restored.Snapshot
+	if err != nil {
+		b.reply(ctx, "restore failed, current save kept: "+err.Error())
+		if snapshot != "" {
+			b.alert(ctx, "restore failed and the previous save could not be moved back; it is at "+snapshot)
+			return
+		}
+		if status.Running {
+			b.startAfterRestore(ctx)
+		}
+		return
+	}
+	if restored.OwnerErr != nil {
+		b.log.Warn("restored files keep the bot's owner", "err", restored.OwnerErr.Error())
+		b.reply(ctx, "warning: "+restored.OwnerErr.Error()+"; the game server may be unable to write its save")
+	}
+
+	if err := b.docker.Start(ctx); err != nil {
+		b.rollbackRestore(ctx, saveDir, snapshot, status.Running, "failed to start server: "+err.Error())
+		return
+	}
+	if b.supervisor != nil {
+		b.supervisor.Reset()
+	}
+	took, err := b.waitReady(ctx, b.cfg.ReadyTimeout)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		b.rollbackRestore(ctx, saveDir, snapshot, status.Running, "server did not come up: "+err.Error())
+		return
+	}
+
+	msg := "backup " + archive.ID + " restored; server is up (took " + formatSeconds(took) + ")"
+	if snapshot != "" {
+		msg += "; previous save kept at " + snapshot
+	}
+	b.reply(ctx, msg)
+}
+
+// rollbackRestore puts the safety snapshot back after a restored save failed
+// to boot and returns the server to the state it was in before the restore.
+func (b *Bot) rollbackRestore(ctx context.Context, saveDir, snapshot string, wasRunning bool, reason string) {
+	b.reply(ctx, "restore failed ("+reason+"); rolling back to the previous save")
+	status, err := b.docker.Status(ctx)
+	if err == nil && status.Running {
+		if err := b.stopContainer(ctx); err != nil {
+			b.alert(ctx, "rollback failed: could not stop server: "+err.Error()+"; previous save is at "+snapshot)
+			return
+		}
+	}
+	if err := backup.Rollback(saveDir, snapshot); err != nil {
+		msg := "rollback failed: " + err.Error()
+		if snapshot != "" {
+			msg += "; previous save is at " + snapshot
+		}
+		b.alert(ctx, msg)
+		return
+	}
+	if !wasRunning {
+		b.reply(ctx, "rolled back to the previous save; server left stopped")
+		return
+	}
+	b.startAfterRestore(ctx)
+}
+
+func (b *Bot) startAfterRestore(ctx context.Context) {
+	if err := b.docker.Start(ctx); err != nil {
+		b.alert(ctx, "failed to start server with the previous save: "+err.Error())
+		return
+	}
+	took, err := b.waitReady(ctx, b.cfg.ReadyTimeout)
+	switch {
+	case err == nil:
+		b.reply(ctx, "server is back up with the previous save (took "+formatSeconds(took)+")")
+	case ctx.Err() != nil:
+	default:
+		b.alert(ctx, "server did not come back with the previous save: "+err.Error())
+	}
+}
@@ -0,0 +1,36 @@
+package matrix
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"pikabot/internal/backup"
+)
+
+func TestRestoreConfirmRefusalKeepsRestoreArmed(t *testing.T) {
+	b, room := newTestBot(t, &fakeGame{})
+	ctx := context.Background()
+	const admin = "@admin:example.org"
+	b.pendingRestore = &pendingRestore{archive: backup.Archive{ID: "20240301-0200"}, sender: admin, expires: time.Now().Add(time.Minute)}
+
+	b.busy.Store(true)
+	b.handleRestore(ctx, admin, []string{"confirm"})
+	b.busy.Store(false)
+	if b.restoreFor(admin, false) == nil {
+		t.Fatal("restore was dropped after a busy refusal")
+	}
+
+	b.pendingStop = &countdown{cancel: func() {}, deadline: time.Now().Add(time.Hour)}
+	b.handleRestore(ctx, admin, []string{"confirm"})
+	b.pendingStop = nil
+	if b.restoreFor(admin, false) == nil {
+		t.Fatal("restore was dropped after a countdown refusal")
+	}
+
+	want := []string{"busy, try again", "refused to restore: a countdown is pending; use cancelstop first"}
+	if got := room.sent(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("room messages %q want %q", got, want)
+	}
+}
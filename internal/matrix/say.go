@@ -0,0 +1,44 @@
+package matrix
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"maunium.net/go/mautrix/id"
+)
+
+func (b *Bot) handleSay(ctx context.Context, sender id.UserID, args []string) {
+	message := strings.Join(args, " ")
+	if message == "" {
+		b.reply(ctx, "usage: say <message>")
+		return
+	}
+
+	sayCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := b.game.Broadcast(sayCtx, b.displayName(ctx, sender)+": "+message); err != nil {
+		b.reply(ctx, "failed to send message in-game: "+err.Error())
+		return
+	}
+	b.reply(ctx, "sent")
+}
+
+// displayName returns the sender's Matrix display name, falling back to the
+// localpart of the MXID when the profile lookup fails or has no name.
+func (b *Bot) displayName(ctx context.Context, userID id.UserID) string {
+	lookupCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	resp, err := b.matrix.GetDisplayName(lookupCtx, userID)
+	if err == nil && strings.TrimSpace(resp.DisplayName) != "" {
+		return strings.TrimSpace(resp.DisplayName)
+	}
+	if err != nil {
+		b.log.Debug("display name lookup failed", "user", userID.String(), "err", err.Error())
+	}
+	localpart, _, err := userID.Parse()
+	if err != nil || localpart == "" {
+		return userID.String()
+	}
+	return localpart
+}
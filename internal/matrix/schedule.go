@@ -0,0 +1,121 @@
+package matrix
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"pikabot/internal/schedule"
+)
+
+func (b *Bot) watchSchedules(ctx context.Context) {
+	cursor := schedule.NewCursor(time.Now)
+	for {
+		// Wake up just after each minute boundary.
+		next := time.Now().Truncate(time.Minute).Add(time.Minute + time.Second)
+		if !sleepUntil(ctx, next) {
+			return
+		}
+
+		// A schedule can run for minutes; the cursor replays the minutes
+		// that passed meanwhile so their entries still fire.
+		for _, minute := range cursor.Advance() {
+			for _, entry := range b.schedules.List() {
+				if entry.Cron().Matches(minute) {
+					b.runSchedule(ctx, entry)
+				}
+			}
+		}
+	}
+}
+
+// runSchedule goes through the same handlers as the chat commands, so a
+// scheduled stop keeps the player check and never stops a server with
+// players online.
+func (b *Bot) runSchedule(ctx context.Context, entry schedule.Entry) {
+	waitCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	acquired := b.acquireBusy(waitCtx)
+	cancel()
+	if !acquired {
+		b.reply(ctx, fmt.Sprintf("scheduled %s (%s) skipped: bot is busy", entry.Action, entry.ID))
+		return
+	}
+	defer b.busy.Store(false)
+
+	b.log.Info("running schedule", "id", entry.ID, "action", string(entry.Action), "spec", entry.Spec)
+	b.reply(ctx, fmt.Sprintf("scheduled %s (%s, %s)", entry.Action, entry.ID, entry.Spec))
+	switch entry.Action {
+	case schedule.Start:
+		b.handleStart(ctx)
+	case schedule.Stop:
+		b.handleStop(ctx)
+	case schedule.Backup:
+		b.handleBackup(ctx)
+	}
+}
+
+func (b *Bot) handleSchedule(ctx context.Context, args []string) {
+	usage := "usage: schedule list | schedule add <start|stop|backup> <minute> <hour> <day> <month> <weekday> | schedule remove <id>"
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "list":
+		b.listSchedules(ctx)
+	case "add":
+		if len(args) < 3 {
+			b.reply(ctx, usage)
+			return
+		}
+		action, err := schedule.ParseAction(args[1])
+		if err != nil {
+			b.reply(ctx, err.Error())
+			return
+		}
+		entry, err := b.schedules.Add(action, strings.Join(args[2:], " "))
+		if err != nil {
+			b.reply(ctx, "failed to add schedule: "+err.Error())
+			return
+		}
+		b.reply(ctx, fmt.Sprintf("added schedule %s: %s at %s (next %s)", entry.ID, entry.Action, entry.Spec, formatNextRun(entry)))
+	case "remove":
+		if len(args) != 2 {
+			b.reply(ctx, usage)
+			return
+		}
+		if err := b.schedules.Remove(args[1]); err != nil {
+			b.reply(ctx, "failed to remove schedule: "+err.Error())
+			return
+		}
+		b.reply(ctx, "removed schedule "+args[1])
+	default:
+		b.reply(ctx, usage)
+	}
+}
+
+func (b *Bot) listSchedules(ctx context.Context) {
+	entries := b.schedules.List()
+	if len(entries) == 0 {
+		b.reply(ctx, "no schedules configured")
+		return
+	}
+	lines := []string{"Schedules (" + time.Local.String() + "):"}
+	for _, entry := range entries {
+		line := fmt.Sprintf("- %s: %s at %s, next %s", entry.ID, entry.Action, entry.Spec, formatNextRun(entry))
+		if entry.Fixed {
+			line += " (from config)"
+		}
+		lines = append(lines, line)
+	}
+	b.reply(ctx, strings.Join(lines, "\n"))
+}
+
+func formatNextRun(entry schedule.Entry) string {
+	next := entry.Cron().Next(time.Now())
+	if next.IsZero() {
+		return "never"
+	}
+	return next.Format("Mon 2006-01-02 15:04")
+}
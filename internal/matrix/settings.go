@@ -0,0 +1,83 @@
+package matrix
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"pikabot/internal/fsutil"
+
+	"maunium.net/go/mautrix/id"
+)
+
+type RoomSettings struct {
+	AnnouncePresence bool `json:"announce_presence"`
+}
+
+type SettingsStore struct {
+	mu       sync.Mutex
+	path     string
+	defaults RoomSettings
+	rooms    map[id.RoomID]RoomSettings
+}
+
+func NewSettingsStore(path string, defaults RoomSettings) (*SettingsStore, error) {
+	s := &SettingsStore{
+		path:     path,
+		defaults: defaults,
+		rooms:    make(map[id.RoomID]RoomSettings),
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("read settings: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.rooms); err != nil {
+		return nil, fmt.Errorf("parse settings %s: %w", path, err)
+	}
+	return s, nil
+}
+
+func (s *SettingsStore) Room(roomID id.RoomID) RoomSettings {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if settings, ok := s.rooms[roomID]; ok {
+		return settings
+	}
+	return s.defaults
+}
+
+func (s *SettingsStore) Update(roomID id.RoomID, fn func(*RoomSettings)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous, ok := s.rooms[roomID]
+	settings := previous
+	if !ok {
+		settings = s.defaults
+	}
+	fn(&settings)
+	s.rooms[roomID] = settings
+
+	if err := s.save(); err != nil {
+		// A setting that did not reach the disk would be lost on restart.
+		if ok {
+			s.rooms[roomID] = previous
+		} else {
+			delete(s.rooms, roomID)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *SettingsStore) save() error {
+	data, err := json.MarshalIndent(s.rooms, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fsutil.WriteFileAtomic(s.path, append(data, '\n'), 0o600)
+}
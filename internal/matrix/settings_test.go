@@ -0,0 +1,43 @@
+package matrix
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"maunium.net/go/mautrix/id"
+)
+
+func TestSettingsUpdateRollsBackFailedWrite(t *testing.T) {
+	const room = id.RoomID("!room:example.org")
+	dir := t.TempDir()
+	s, err := NewSettingsStore(filepath.Join(dir, "settings.json"), RoomSettings{AnnouncePresence: true})
+	if err != nil {
+		t.Fatalf("NewSettingsStore(): %v", err)
+	}
+
+	// A regular file where the directory should be makes every write fail.
+	blocked := filepath.Join(dir, "blocked")
+	if err := os.WriteFile(blocked, nil, 0o600); err != nil {
+		t.Fatalf("WriteFile(): %v", err)
+	}
+	s.path = filepath.Join(blocked, "settings.json")
+	if err := s.Update(room, func(rs *RoomSettings) { rs.AnnouncePresence = false }); err == nil {
+		t.Fatal("Update() expected write error")
+	}
+	if got := s.Room(room); !got.AnnouncePresence {
+		t.Fatalf("Room() after failed first write got %+v, want defaults", got)
+	}
+
+	s.path = filepath.Join(dir, "settings.json")
+	if err := s.Update(room, func(rs *RoomSettings) { rs.AnnouncePresence = false }); err != nil {
+		t.Fatalf("Update(): %v", err)
+	}
+	s.path = filepath.Join(blocked, "settings.json")
+	if err := s.Update(room, func(rs *RoomSettings) { rs.AnnouncePresence = true }); err == nil {
+		t.Fatal("Update() expected write error")
+	}
+	if got := s.Room(room); got.AnnouncePresence {
+		t.Fatalf("Room() after failed write got %+v, want the previous setting", got)
+	}
+}
@@ -0,0 +1,115 @@
+package matrix
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"pikabot/internal/dockerctl"
+)
+
+// memoryRearmMargin is how far memory has to drop below the threshold
+// before another warning can fire, so hovering at the limit stays quiet.
+const memoryRearmMargin = 5.0
+
+func (b *Bot) handleStats(ctx context.Context) {
+	status, err := b.docker.Status(ctx)
+	if err != nil {
+		b.reply(ctx, "error checking server status: "+err.Error())
+		return
+	}
+	if !status.Exists || !status.Running {
+		b.reply(ctx, "server is not running")
+		return
+	}
+
+	stats, err := b.containerStats(ctx)
+	if err != nil {
+		b.reply(ctx, "failed to read container stats: "+err.Error())
+		return
+	}
+
+	summary := &summary{title: "Palworld resource usage"}
+	summary.add("CPU", fmt.Sprintf("%.1f%%", stats.CPUPercent))
+	memory := formatBytes(stats.MemoryUsage)
+	if stats.MemoryLimit > 0 {
+		memory += fmt.Sprintf(" / %s (%.1f%%)", formatBytes(stats.MemoryLimit), stats.MemoryPercent)
+	}
+	summary.add("Memory", memory)
+	summary.add("Network I/O", formatBytes(stats.NetworkRx)+" in / "+formatBytes(stats.NetworkTx)+" out")
+	summary.add("Block I/O", formatBytes(stats.BlockRead)+" read / "+formatBytes(stats.BlockWrite)+" written")
+	b.replyHTML(ctx, summary.plain(), summary.html())
+}
+
+func (b *Bot) containerStats(ctx context.Context) (dockerctl.Stats, error) {
+	statsCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	return b.docker.Stats(statsCtx)
+}
+
+type memoryAlarm struct {
+	threshold float64
+	fired     bool
+}
+
+// observe reports true once per excursion above the threshold.
+func (a *memoryAlarm) observe(percent float64) bool {
+	if a.fired {
+		if percent < a.threshold-memoryRearmMargin {
+			a.fired = false
+		}
+		return false
+	}
+	if percent >= a.threshold {
+		a.fired = true
+		return true
+	}
+	return false
+}
+
+func (b *Bot) watchMemory(ctx context.Context) {
+	alarm := &memoryAlarm{threshold: float64(b.cfg.MemoryWarnPercent)}
+	ticker := time.NewTicker(b.cfg.StatsPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		status, err := b.docker.Status(ctx)
+		if err != nil || !status.Exists || !status.Running {
+			continue
+		}
+		stats, err := b.containerStats(ctx)
+		if err != nil {
+			b.log.Warn("memory check: docker stats failed", "err", err.Error())
+			continue
+		}
+		if stats.MemoryLimit == 0 || !alarm.observe(stats.MemoryPercent) {
+			continue
+		}
+
+		msg := fmt.Sprintf("warning: Palworld memory usage is %.1f%% (%s / %s), above the %d%% threshold",
+			stats.MemoryPercent, formatBytes(stats.MemoryUsage), formatBytes(stats.MemoryLimit), b.cfg.MemoryWarnPercent)
+		if players, err := b.onlinePlayers(ctx); err == nil && len(players) == 0 {
+			msg += "; nobody is online, send " + b.cfg.CommandPrefix + "restartpal to restart now"
+		}
+		b.reply(ctx, msg)
+	}
+}
+
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,40 @@
+package matrix
+
+import "testing"
+
+func TestMemoryAlarm(t *testing.T) {
+	alarm := &memoryAlarm{threshold: 80}
+	steps := []struct {
+		percent float64
+		want    bool
+	}{
+		{percent: 50, want: false},
+		{percent: 81, want: true},
+		{percent: 90, want: false},
+		{percent: 77, want: false},
+		{percent: 82, want: false},
+		{percent: 74, want: false},
+		{percent: 80, want: true},
+	}
+	for i, step := range steps {
+		if got := alarm.observe(step.percent); got != step.want {
+			t.Fatalf("step %d: observe(%v) got %v want %v", i, step.percent, got, step.want)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    uint64
+		want string
+	}{
+		{n: 512, want: "512 B"},
+		{n: 1536, want: "1.5 KiB"},
+		{n: 8 << 30, want: "8.0 GiB"},
+	}
+	for _, tt := range tests {
+		if got := formatBytes(tt.n); got != tt.want {
+			t.Fatalf("formatBytes(%d) got %q want %q", tt.n, got, tt.want)
+		}
+	}
+}
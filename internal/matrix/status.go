@@ -0,0 +1,160 @@
+package matrix
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"pikabot/internal/game"
+
+	"maunium.net/go/mautrix/event"
+)
+
+func (b *Bot) handleStatus(ctx context.Context) {
+	status, err := b.docker.Status(ctx)
+	if err != nil {
+		b.reply(ctx, "error checking server status: "+err.Error())
+		return
+	}
+	if !status.Exists {
+		b.reply(ctx, "configured container was not found")
+		return
+	}
+
+	summary := &summary{title: "Palworld server status"}
+	state := status.State
+	if state == "" {
+		state = "unknown"
+	}
+	if status.Running && !status.StartedAt.IsZero() {
+		state += " (up " + formatUptime(time.Since(status.StartedAt)) + ")"
+	}
+	summary.add("Container", state)
+
+	if !status.Running {
+		b.replyHTML(ctx, summary.plain(), summary.html())
+		return
+	}
+
+	infoCtx, cancelInfo := context.WithTimeout(ctx, 5*time.Second)
+	info, err := b.game.Info(infoCtx)
+	cancelInfo()
+	if err != nil {
+		summary.add("Server", "unavailable (game server did not answer)")
+		b.log.Warn("status: server info failed", "err", err.Error())
+	} else {
+		summary.add("Server", fmt.Sprintf("%s (%s)", info.Name, info.Version))
+	}
+
+	players, err := b.onlinePlayers(ctx)
+	if err != nil {
+		summary.add("Players", "unknown (game server error)")
+		b.log.Warn("status: player check failed", "err", err.Error())
+	} else {
+		summary.addList(fmt.Sprintf("Players (%d)", len(players)), game.Names(players), "nobody online")
+	}
+
+	b.replyHTML(ctx, summary.plain(), summary.html())
+}
+
+// summary renders the same key/value report as plain text for clients
+// without HTML support and as formatted HTML for the rest.
+type summary struct {
+	title string
+	rows  []summaryRow
+}
+
+type summaryRow struct {
+	label string
+	value string
+	items []string
+}
+
+func (s *summary) add(label, value string) {
+	s.rows = append(s.rows, summaryRow{label: label, value: value})
+}
+
+func (s *summary) addList(label string, items []string, empty string) {
+	if len(items) == 0 {
+		s.add(label, empty)
+		return
+	}
+	s.rows = append(s.rows, summaryRow{label: label, items: items})
+}
+
+func (s *summary) plain() string {
+	var builder strings.Builder
+	builder.WriteString(s.title)
+	for _, row := range s.rows {
+		builder.WriteString("\n" + row.label + ": ")
+		if row.items == nil {
+			builder.WriteString(row.value)
+			continue
+		}
+		builder.WriteString(strings.Join(row.items, ", "))
+	}
+	return builder.String()
+}
+
+func (s *summary) html() string {
+	var builder strings.Builder
+	builder.WriteString("<strong>" + html.EscapeString(s.title) + "</strong><ul>")
+	for _, row := range s.rows {
+		builder.WriteString("<li><strong>" + html.EscapeString(row.label) + ":</strong> ")
+		if row.items == nil {
+			builder.WriteString(html.EscapeString(row.value))
+		} else {
+			builder.WriteString("<ul>")
+			for _, item := range row.items {
+				builder.WriteString("<li>" + html.EscapeString(item) + "</li>")
+			}
+			builder.WriteString("</ul>")
+		}
+		builder.WriteString("</li>")
+	}
+	builder.WriteString("</ul>")
+	return builder.String()
+}
+
+func (b *Bot) replyHTML(ctx context.Context, plain, formatted string) {
+	content := &event.MessageEventContent{
+		MsgType:       event.MsgText,
+		Body:          plain,
+		Format:        event.FormatHTML,
+		FormattedBody: formatted,
+	}
+	if _, err := b.matrix.SendMessageEvent(ctx, b.roomID, event.EventMessage, content); err != nil {
+		b.log.Error("failed sending matrix message", "err", err.Error())
+	}
+}
+
+// alert is reply for things that need someone to act: it pings the room.
+func (b *Bot) alert(ctx context.Context, text string) {
+	content := &event.MessageEventContent{
+		MsgType:       event.MsgText,
+		Body:          "@room " + text,
+		Format:        event.FormatHTML,
+		FormattedBody: "@room <strong>" + html.EscapeString(text) + "</strong>",
+		Mentions:      &event.Mentions{Room: true},
+	}
+	if _, err := b.matrix.SendMessageEvent(ctx, b.roomID, event.EventMessage, content); err != nil {
+		b.log.Error("failed sending matrix message", "err", err.Error())
+	}
+}
+
+func formatUptime(d time.Duration) string {
+	d = d.Round(time.Minute)
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh %dm", days, hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
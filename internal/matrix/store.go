@@ -4,10 +4,11 @@ import (
 	"context"
 	"errors"
 	"os"
-	"path/filepath"
 	"strings"
 	"sync"
 
+	"pikabot/internal/fsutil"
+
 	"maunium.net/go/mautrix/id"
 )
 
@@ -40,7 +41,7 @@ func (s *FileSyncStore) LoadFilterID(_ context.Context, userID id.UserID) (strin
 func (s *FileSyncStore) SaveNextBatch(_ context.Context, _ id.UserID, nextBatchToken string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	return writeFileAtomically(s.path, []byte(strings.TrimSpace(nextBatchToken)+"\n"), 0o600)
+	return fsutil.WriteFileAtomic(s.path, []byte(strings.TrimSpace(nextBatchToken)+"\n"), 0o600)
 }
 
 func (s *FileSyncStore) LoadNextBatch(_ context.Context, _ id.UserID) (string, error) {
@@ -55,14 +56,3 @@ func (s *FileSyncStore) LoadNextBatch(_ context.Context, _ id.UserID) (string, e
 	}
 	return strings.TrimSpace(string(data)), nil
 }
-
-func writeFileAtomically(path string, data []byte, perm os.FileMode) error {
-	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
-		return err
-	}
-	tmp := path + ".tmp"
-	if err := os.WriteFile(tmp, data, perm); err != nil {
-		return err
-	}
-	return os.Rename(tmp, path)
-}
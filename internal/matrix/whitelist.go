@@ -0,0 +1,134 @@
+package matrix
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"pikabot/internal/moderation"
+
+	"maunium.net/go/mautrix/id"
+)
+
+// whitelistActor is recorded in the audit log for automatic kicks.
+const whitelistActor = "whitelist"
+
+func (b *Bot) handleWhitelist(ctx context.Context, sender id.UserID, args []string) {
+	usage := "usage: whitelist list | whitelist add <steamid> | whitelist remove <steamid>"
+	if len(args) == 0 || strings.ToLower(args[0]) == "list" {
+		b.listWhitelist(ctx)
+		return
+	}
+	if len(args) != 2 {
+		b.reply(ctx, usage)
+		return
+	}
+
+	steamID := moderation.CanonicalSteamID(args[1])
+	switch strings.ToLower(args[0]) {
+	case "add":
+		added, err := b.whitelist.Add(args[1])
+		if err != nil {
+			b.reply(ctx, "failed to add to whitelist: "+err.Error())
+			return
+		}
+		if !added {
+			b.reply(ctx, steamID+" is already whitelisted")
+			return
+		}
+		b.audit(ctx, moderation.Entry{Actor: sender.String(), Action: moderation.WhitelistAdd, SteamID: steamID}, nil)
+		b.reply(ctx, "whitelisted "+steamID)
+	case "remove":
+		removed, err := b.whitelist.Remove(args[1])
+		if err != nil {
+			b.reply(ctx, "failed to remove from whitelist: "+err.Error())
+			return
+		}
+		if !removed {
+			b.reply(ctx, steamID+" is not whitelisted")
+			return
+		}
+		b.audit(ctx, moderation.Entry{Actor: sender.String(), Action: moderation.WhitelistRemove, SteamID: steamID}, nil)
+		b.reply(ctx, "removed "+steamID+" from the whitelist")
+	default:
+		b.reply(ctx, usage)
+	}
+}
+
+func (b *Bot) listWhitelist(ctx context.Context) {
+	ids := b.whitelist.List()
+	state := "not enforced (WHITELIST_ENFORCE=false)"
+	if b.cfg.WhitelistEnforce {
+		state = "enforced"
+		if len(ids) == 0 {
+			state = "enforced once it has entries"
+		}
+	}
+	if len(ids) == 0 {
+		b.reply(ctx, "whitelist is empty; "+state)
+		return
+	}
+	b.reply(ctx, "Whitelist ("+state+"):\n- "+strings.Join(ids, "\n- "))
+}
+
+func (b *Bot) watchWhitelist(ctx context.Context) {
+	ticker := time.NewTicker(b.cfg.WhitelistPollInterval)
+	defer ticker.Stop()
+
+	enforcer := moderation.NewEnforcer()
+	lastProblem := ""
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			lastProblem = b.checkWhitelist(ctx, enforcer, lastProblem)
+		}
+	}
+}
+
+// checkWhitelist kicks confirmed non-whitelisted players. It returns the
+// current problem with the player list, if any, so the same one is only
+// reported to the room once.
+func (b *Bot) checkWhitelist(ctx context.Context, enforcer *moderation.Enforcer, lastProblem string) string {
+	players, err := b.onlinePlayers(ctx)
+	if err != nil {
+		// No list, no verdict; sightings must be consecutive.
+		enforcer.Reset()
+		b.log.Debug("whitelist check skipped: player check failed", "err", err.Error())
+		return lastProblem
+	}
+
+	kick, err := enforcer.Evaluate(players, b.whitelist.Contains, b.whitelist.Len() == 0)
+	if err != nil {
+		problem := err.Error()
+		var tooMany *moderation.TooManyViolatorsError
+		switch {
+		case problem == lastProblem:
+		case errors.Is(err, moderation.ErrMalformedPlayerList):
+			b.log.Warn("whitelist check skipped", "err", problem)
+			b.alert(ctx, "whitelist: "+problem+"; enforcement is paused for everyone until the list is valid again")
+		case errors.As(err, &tooMany):
+			b.alert(ctx, "whitelist: "+problem+"; check the player list and kick manually if needed")
+		}
+		return problem
+	}
+	if lastProblem != "" {
+		b.reply(ctx, "whitelist: the player list checks out again; enforcement resumed")
+	}
+
+	for _, player := range kick {
+		player.SteamID = moderation.CanonicalSteamID(player.SteamID)
+		kickCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		err := b.game.KickPlayer(kickCtx, player.SteamID)
+		cancel()
+		b.audit(ctx, moderation.Entry{Actor: whitelistActor, Action: moderation.Kick, SteamID: player.SteamID, Name: player.Name, Reason: "not whitelisted"}, err)
+		if err != nil {
+			b.reply(ctx, "whitelist: failed to kick "+describePlayer(player)+": "+err.Error())
+			continue
+		}
+		b.reply(ctx, "whitelist: kicked "+describePlayer(player)+" (not whitelisted)")
+	}
+	return ""
+}
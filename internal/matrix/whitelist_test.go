@@ -0,0 +1,77 @@
+package matrix
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"pikabot/internal/game"
+	"pikabot/internal/moderation"
+)
+
+func TestWhitelistKicksUseCanonicalSteamIDs(t *testing.T) {
+	g := &fakeGame{players: []game.Player{
+		{Name: "Alice", SteamID: "steam_76561198000000001"},
+		{Name: "Mallory", SteamID: "76561198000000666"},
+	}}
+	b, _ := newTestBot(t, g)
+	whitelist, err := moderation.NewWhitelist(filepath.Join(b.cfg.DataDir, "whitelist.json"))
+	if err != nil {
+		t.Fatalf("NewWhitelist(): %v", err)
+	}
+	if _, err := whitelist.Add("76561198000000001"); err != nil {
+		t.Fatalf("Add(): %v", err)
+	}
+	b.whitelist = whitelist
+
+	enforcer := moderation.NewEnforcer()
+	for i := 0; i < moderation.WhitelistConfirmations; i++ {
+		b.checkWhitelist(context.Background(), enforcer, "")
+	}
+
+	want := []string{"steam_76561198000000666"}
+	if !reflect.DeepEqual(g.kicked, want) {
+		t.Fatalf("kicked %v want %v", g.kicked, want)
+	}
+	if got := auditedSteamIDs(t, filepath.Join(b.cfg.DataDir, "audit.jsonl")); !reflect.DeepEqual(got, want) {
+		t.Fatalf("audit log Steam IDs %v want %v", got, want)
+	}
+}
+
+func TestWhitelistAlertsOncePerMalformedPlayerList(t *testing.T) {
+	g := &fakeGame{players: []game.Player{
+		{Name: "Alice", SteamID: "steam_76561198000000001"},
+		{Name: "Newcomer", SteamID: "steam_00000000000000000"},
+	}}
+	b, room := newTestBot(t, g)
+	whitelist, err := moderation.NewWhitelist(filepath.Join(b.cfg.DataDir, "whitelist.json"))
+	if err != nil {
+		t.Fatalf("NewWhitelist(): %v", err)
+	}
+	if _, err := whitelist.Add("76561198000000001"); err != nil {
+		t.Fatalf("Add(): %v", err)
+	}
+	b.whitelist = whitelist
+
+	ctx := context.Background()
+	enforcer := moderation.NewEnforcer()
+	problem := ""
+	for i := 0; i < 3; i++ {
+		problem = b.checkWhitelist(ctx, enforcer, problem)
+	}
+	g.players = g.players[:1]
+	problem = b.checkWhitelist(ctx, enforcer, problem)
+
+	if len(g.kicked) != 0 {
+		t.Fatalf("kicked %v want none", g.kicked)
+	}
+	if problem != "" {
+		t.Fatalf("problem %q after a valid list, want none", problem)
+	}
+	sent := room.sent()
+	if len(sent) != 2 || !strings.Contains(sent[0], "Newcomer") || !strings.Contains(sent[1], "enforcement resumed") {
+		t.Fatalf("room messages %q, want one alert naming the player and one resume notice", sent)
+	}
+}
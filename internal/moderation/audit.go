@@ -0,0 +1,73 @@
+package moderation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type Action string
+
+const (
+	Kick  Action = "kick"
+	Ban   Action = "ban"
+	Unban Action = "unban"
+
+	WhitelistAdd    Action = "whitelist-add"
+	WhitelistRemove Action = "whitelist-remove"
+)
+
+// Entry is one line of the audit log.
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Actor   string    `json:"actor"`
+	Action  Action    `json:"action"`
+	SteamID string    `json:"steam_id"`
+	Name    string    `json:"name,omitempty"`
+	Reason  string    `json:"reason,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// AuditLog appends moderation actions as JSON lines. Entries are never
+// rewritten, so the file survives restarts and can be inspected with jq.
+type AuditLog struct {
+	mu   sync.Mutex
+	path string
+	now  func() time.Time
+}
+
+func NewAuditLog(path string) *AuditLog {
+	return &AuditLog{path: path, now: time.Now}
+}
+
+func (l *AuditLog) Record(entry Entry) error {
+	if entry.Time.IsZero() {
+		entry.Time = l.now().UTC()
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return fmt.Errorf("create audit log directory: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("write audit log: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("write audit log: %w", err)
+	}
+	return f.Close()
+}
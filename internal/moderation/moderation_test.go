@@ -0,0 +1,191 @@
+package moderation
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"pikabot/internal/game"
+)
+
+func TestResolve(t *testing.T) {
+	alice := game.Player{Name: "Alice", PlayerUID: "1a2b3c", SteamID: "steam_76561198000000001"}
+	alicia := game.Player{Name: "Alicia", PlayerUID: "4d5e6f", SteamID: "steam_76561198000000002"}
+	bob := game.Player{Name: "bob", PlayerUID: "778899", SteamID: "76561198000000003"}
+	players := []game.Player{alice, alicia, bob}
+
+	tests := []struct {
+		name      string
+		query     string
+		want      game.Player
+		ambiguous []game.Player
+		notFound  bool
+	}{
+		{name: "unique prefix", query: "b", want: bob},
+		{name: "case-insensitive prefix", query: "ALICI", want: alicia},
+		{name: "exact name beats longer names", query: "alice", want: alice},
+		{name: "ambiguous prefix", query: "Ali", ambiguous: []game.Player{alice, alicia}},
+		{name: "steam id", query: "steam_76561198000000002", want: alicia},
+		{name: "steam id without prefix", query: "76561198000000001", want: alice},
+		{name: "steam prefix added", query: "steam_76561198000000003", want: bob},
+		{name: "player uid", query: "778899", want: bob},
+		{name: "unknown", query: "carol", notFound: true},
+		{name: "empty", query: " ", notFound: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Resolve(players, tt.query)
+			var ambiguous *AmbiguousError
+			var notFound *NotFoundError
+			switch {
+			case tt.ambiguous != nil:
+				if !errors.As(err, &ambiguous) || !reflect.DeepEqual(ambiguous.Candidates, tt.ambiguous) {
+					t.Fatalf("Resolve(%q) got %+v, %v want ambiguous %+v", tt.query, got, err, tt.ambiguous)
+				}
+			case tt.notFound:
+				if !errors.As(err, &notFound) {
+					t.Fatalf("Resolve(%q) got %+v, %v want not found", tt.query, got, err)
+				}
+			default:
+				if err != nil || got != tt.want {
+					t.Fatalf("Resolve(%q) got %+v, %v want %+v", tt.query, got, err, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveDuplicateExactNames(t *testing.T) {
+	a := game.Player{Name: "Pal", SteamID: "steam_1"}
+	b := game.Player{Name: "pal", SteamID: "steam_2"}
+	_, err := Resolve([]game.Player{a, b}, "pal")
+	var ambiguous *AmbiguousError
+	if !errors.As(err, &ambiguous) || len(ambiguous.Candidates) != 2 {
+		t.Fatalf("Resolve() with duplicate names got %v, want ambiguous", err)
+	}
+}
+
+func TestResolveLeading(t *testing.T) {
+	bigBob := game.Player{Name: "Big Bob", SteamID: "steam_76561198000000004"}
+	bob := game.Player{Name: "Bob", SteamID: "steam_76561198000000005"}
+	bobby := game.Player{Name: "Bobby Tables", SteamID: "steam_76561198000000006"}
+	players := []game.Player{bigBob, bob, bobby}
+
+	tests := []struct {
+		name     string
+		words    []string
+		want     game.Player
+		rest     []string
+		notFound bool
+		wantErr  bool
+	}{
+		{name: "multi-word name", words: []string{"big", "bob"}, want: bigBob, rest: []string{}},
+		{name: "name then reason", words: []string{"Big", "Bob", "griefing", "bases"}, want: bigBob, rest: []string{"griefing", "bases"}},
+		{name: "exact short name then reason", words: []string{"bob", "spam"}, want: bob, rest: []string{"spam"}},
+		{name: "prefix of a multi-word name", words: []string{"Bobby", "T"}, want: bobby, rest: []string{}},
+		{name: "ambiguous run is not shortened", words: []string{"Bo"}, wantErr: true},
+		{name: "unknown", words: []string{"carol", "spam"}, notFound: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, rest, err := ResolveLeading(players, tt.words)
+			var notFound *NotFoundError
+			switch {
+			case tt.notFound:
+				if !errors.As(err, &notFound) || notFound.Query != "carol spam" {
+					t.Fatalf("ResolveLeading(%q) got %+v, %v want not found for the whole query", tt.words, got, err)
+				}
+			case tt.wantErr:
+				if err == nil || errors.As(err, &notFound) {
+					t.Fatalf("ResolveLeading(%q) got %+v, %v want ambiguous", tt.words, got, err)
+				}
+			default:
+				if err != nil || got != tt.want || !reflect.DeepEqual(rest, tt.rest) {
+					t.Fatalf("ResolveLeading(%q) got %+v, %q, %v want %+v, %q", tt.words, got, rest, err, tt.want, tt.rest)
+				}
+			}
+		})
+	}
+}
+
+func TestLooksLikeSteamID(t *testing.T) {
+	tests := map[string]bool{
+		"steam_76561198000000001": true,
+		"76561198000000001":       true,
+		"STEAM_76561198000000001": true,
+		"7656119800000000":        false,
+		"steam_7656119800000000x": false,
+		"steam_00000000000000000": false,
+		"00000000000000000":       false,
+		"Alice":                   false,
+	}
+	for value, want := range tests {
+		if got := LooksLikeSteamID(value); got != want {
+			t.Fatalf("LooksLikeSteamID(%q) got %v want %v", value, got, want)
+		}
+	}
+}
+
+func TestCanonicalSteamID(t *testing.T) {
+	tests := map[string]string{
+		"steam_76561198000000001":   "steam_76561198000000001",
+		"76561198000000001":         "steam_76561198000000001",
+		"STEAM_76561198000000001":   "steam_76561198000000001",
+		" steam_76561198000000001 ": "steam_76561198000000001",
+	}
+	for value, want := range tests {
+		if got := CanonicalSteamID(value); got != want {
+			t.Fatalf("CanonicalSteamID(%q) got %q want %q", value, got, want)
+		}
+	}
+}
+
+func TestAuditLogAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	log := NewAuditLog(path)
+	log.now = func() time.Time { return time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC) }
+
+	entries := []Entry{
+		{Actor: "@admin:example.org", Action: Kick, SteamID: "steam_1", Name: "Alice"},
+		{Actor: "@admin:example.org", Action: Ban, SteamID: "steam_2", Name: "Bob", Reason: "griefing"},
+	}
+	for _, entry := range entries {
+		if err := log.Record(entry); err != nil {
+			t.Fatalf("Record(): %v", err)
+		}
+	}
+	// A new AuditLog on the same file must append, not truncate.
+	if err := NewAuditLog(path).Record(Entry{Actor: "@mod:example.org", Action: Unban, SteamID: "steam_2"}); err != nil {
+		t.Fatalf("Record(): %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var got []Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("audit line %q: %v", scanner.Text(), err)
+		}
+		got = append(got, entry)
+	}
+	if len(got) != 3 {
+		t.Fatalf("audit log has %d entries, want 3", len(got))
+	}
+	if got[1].Reason != "griefing" || got[1].Actor != "@admin:example.org" || !got[1].Time.Equal(log.now()) {
+		t.Fatalf("audit entry got %+v", got[1])
+	}
+	if got[2].Action != Unban || got[2].Time.IsZero() {
+		t.Fatalf("audit entry got %+v", got[2])
+	}
+}
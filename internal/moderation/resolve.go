@@ -0,0 +1,115 @@
+package moderation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"pikabot/internal/game"
+)
+
+type NotFoundError struct {
+	Query string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("no online player matches %q", e.Query)
+}
+
+// AmbiguousError lists every online player a name prefix matched.
+type AmbiguousError struct {
+	Query      string
+	Candidates []game.Player
+}
+
+func (e *AmbiguousError) Error() string {
+	names := make([]string, 0, len(e.Candidates))
+	for _, p := range e.Candidates {
+		names = append(names, p.Name+" ("+p.SteamID+")")
+	}
+	return fmt.Sprintf("%q matches several players: %s", e.Query, strings.Join(names, ", "))
+}
+
+// Resolve finds the one online player query refers to. Steam IDs (with or
+// without the "steam_" prefix) and player UIDs must match exactly; names
+// match case-insensitively, an exact name before a prefix. Anything that
+// matches more than one player is rejected.
+func Resolve(players []game.Player, query string) (game.Player, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return game.Player{}, &NotFoundError{Query: query}
+	}
+
+	for _, p := range players {
+		if p.SteamID != "" && sameSteamID(p.SteamID, query) {
+			return p, nil
+		}
+		if p.PlayerUID != "" && strings.EqualFold(p.PlayerUID, query) {
+			return p, nil
+		}
+	}
+
+	var exact, prefix []game.Player
+	lower := strings.ToLower(query)
+	for _, p := range players {
+		name := strings.ToLower(p.Name)
+		switch {
+		case name == lower:
+			exact = append(exact, p)
+		case strings.HasPrefix(name, lower):
+			prefix = append(prefix, p)
+		}
+	}
+	switch {
+	case len(exact) == 1:
+		return exact[0], nil
+	case len(exact) > 1:
+		return game.Player{}, &AmbiguousError{Query: query, Candidates: exact}
+	case len(prefix) == 1:
+		return prefix[0], nil
+	case len(prefix) > 1:
+		return game.Player{}, &AmbiguousError{Query: query, Candidates: prefix}
+	}
+	return game.Player{}, &NotFoundError{Query: query}
+}
+
+// ResolveLeading resolves the longest run of leading words that names one
+// online player, so a command can take a multi-word name followed by free
+// text. It returns the player and the words after the name. An ambiguous
+// run is reported instead of falling back to a shorter one.
+func ResolveLeading(players []game.Player, words []string) (game.Player, []string, error) {
+	for n := len(words); n > 0; n-- {
+		player, err := Resolve(players, strings.Join(words[:n], " "))
+		var notFound *NotFoundError
+		if errors.As(err, &notFound) {
+			continue
+		}
+		if err != nil {
+			return game.Player{}, nil, err
+		}
+		return player, words[n:], nil
+	}
+	return game.Player{}, nil, &NotFoundError{Query: strings.Join(words, " ")}
+}
+
+// LooksLikeSteamID reports whether value is a Steam ID as Palworld prints
+// it ("steam_7656...") or a bare 17-digit SteamID64. The all-zero ID the
+// server shows while a player is still connecting is not one.
+func LooksLikeSteamID(value string) bool {
+	digits := strings.TrimPrefix(strings.ToLower(value), "steam_")
+	if len(digits) != 17 {
+		return false
+	}
+	zero := true
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return false
+		}
+		zero = zero && r == '0'
+	}
+	return !zero
+}
+
+func sameSteamID(a, b string) bool {
+	return strings.EqualFold(strings.TrimPrefix(strings.ToLower(a), "steam_"), strings.TrimPrefix(strings.ToLower(b), "steam_"))
+}
@@ -0,0 +1,198 @@
+package moderation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"pikabot/internal/fsutil"
+	"pikabot/internal/game"
+)
+
+// Whitelist is the persisted set of Steam IDs allowed on the server.
+// IDs are stored in Palworld's "steam_<SteamID64>" form.
+type Whitelist struct {
+	mu   sync.Mutex
+	path string
+	ids  map[string]struct{}
+}
+
+func NewWhitelist(path string) (*Whitelist, error) {
+	w := &Whitelist{path: path, ids: make(map[string]struct{})}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return w, nil
+		}
+		return nil, fmt.Errorf("read whitelist: %w", err)
+	}
+	var ids []string
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return nil, fmt.Errorf("parse whitelist %s: %w", path, err)
+	}
+	for _, id := range ids {
+		if !LooksLikeSteamID(id) {
+			return nil, fmt.Errorf("whitelist %s: invalid Steam ID %q", path, id)
+		}
+		w.ids[CanonicalSteamID(id)] = struct{}{}
+	}
+	return w, nil
+}
+
+// Add returns false if steamID was already listed.
+func (w *Whitelist) Add(steamID string) (bool, error) {
+	if !LooksLikeSteamID(steamID) {
+		return false, fmt.Errorf("invalid Steam ID %q", steamID)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	key := CanonicalSteamID(steamID)
+	if _, ok := w.ids[key]; ok {
+		return false, nil
+	}
+	w.ids[key] = struct{}{}
+	if err := w.save(); err != nil {
+		delete(w.ids, key)
+		return false, err
+	}
+	return true, nil
+}
+
+// Remove returns false if steamID was not listed.
+func (w *Whitelist) Remove(steamID string) (bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	key := CanonicalSteamID(steamID)
+	if _, ok := w.ids[key]; !ok {
+		return false, nil
+	}
+	delete(w.ids, key)
+	if err := w.save(); err != nil {
+		w.ids[key] = struct{}{}
+		return false, err
+	}
+	return true, nil
+}
+
+func (w *Whitelist) Contains(steamID string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, ok := w.ids[CanonicalSteamID(steamID)]
+	return ok
+}
+
+func (w *Whitelist) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.ids)
+}
+
+func (w *Whitelist) List() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	ids := make([]string, 0, len(w.ids))
+	for id := range w.ids {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (w *Whitelist) save() error {
+	ids := make([]string, 0, len(w.ids))
+	for id := range w.ids {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	data, err := json.MarshalIndent(ids, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fsutil.WriteFileAtomic(w.path, append(data, '\n'), 0o600)
+}
+
+// CanonicalSteamID returns steamID in Palworld's "steam_<digits>" form.
+func CanonicalSteamID(steamID string) string {
+	return "steam_" + strings.TrimPrefix(strings.ToLower(strings.TrimSpace(steamID)), "steam_")
+}
+
+// WhitelistConfirmations is how many consecutive checks must see a player
+// who is not whitelisted before they are kicked.
+const WhitelistConfirmations = 2
+
+// MaxWhitelistKicks caps kicks per check; more violators than this at once
+// smells like a bad player list, not a raid.
+const MaxWhitelistKicks = 3
+
+var ErrMalformedPlayerList = errors.New("player list has entries without a valid Steam ID")
+
+type TooManyViolatorsError struct {
+	Count int
+}
+
+func (e *TooManyViolatorsError) Error() string {
+	return fmt.Sprintf("%d players are not whitelisted at once (limit %d); not kicking anyone", e.Count, MaxWhitelistKicks)
+}
+
+// Enforcer decides which players to kick. It is built to fail safe: any
+// doubt about the player list means nobody is kicked.
+type Enforcer struct {
+	seen map[string]int
+}
+
+func NewEnforcer() *Enforcer {
+	return &Enforcer{seen: make(map[string]int)}
+}
+
+// Evaluate returns the players to kick for one ShowPlayers result. A player
+// must be seen off-list in WhitelistConfirmations consecutive checks, an
+// empty whitelist never kicks, and a list with any unparseable Steam ID or
+// more than MaxWhitelistKicks violators is rejected as a whole.
+func (e *Enforcer) Evaluate(players []game.Player, allowed func(string) bool, empty bool) ([]game.Player, error) {
+	var malformed []string
+	for _, p := range players {
+		if !LooksLikeSteamID(p.SteamID) {
+			malformed = append(malformed, strconv.Quote(p.Name))
+		}
+	}
+	if len(malformed) > 0 {
+		e.Reset()
+		return nil, fmt.Errorf("%w (%s)", ErrMalformedPlayerList, strings.Join(malformed, ", "))
+	}
+	if empty {
+		e.Reset()
+		return nil, nil
+	}
+
+	seen := make(map[string]int)
+	var violators, confirmed []game.Player
+	for _, p := range players {
+		if allowed(p.SteamID) {
+			continue
+		}
+		key := CanonicalSteamID(p.SteamID)
+		seen[key] = e.seen[key] + 1
+		violators = append(violators, p)
+		if seen[key] >= WhitelistConfirmations {
+			confirmed = append(confirmed, p)
+		}
+	}
+	e.seen = seen
+
+	if len(violators) > MaxWhitelistKicks {
+		return nil, &TooManyViolatorsError{Count: len(violators)}
+	}
+	for _, p := range confirmed {
+		delete(e.seen, CanonicalSteamID(p.SteamID))
+	}
+	return confirmed, nil
+}
+
+func (e *Enforcer) Reset() {
+	e.seen = make(map[string]int)
+}
@@ -0,0 +1,156 @@
+package moderation
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"pikabot/internal/game"
+)
+
+func TestWhitelistPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "whitelist.json")
+	w, err := NewWhitelist(path)
+	if err != nil {
+		t.Fatalf("NewWhitelist(): %v", err)
+	}
+
+	if added, err := w.Add("76561198000000002"); err != nil || !added {
+		t.Fatalf("Add() got %v, %v", added, err)
+	}
+	if added, err := w.Add("steam_76561198000000001"); err != nil || !added {
+		t.Fatalf("Add() got %v, %v", added, err)
+	}
+	if added, _ := w.Add("STEAM_76561198000000002"); added {
+		t.Fatal("Add() of a listed ID in another spelling should report false")
+	}
+	if _, err := w.Add("Alice"); err == nil {
+		t.Fatal("Add() of a name expected error")
+	}
+
+	reloaded, err := NewWhitelist(path)
+	if err != nil {
+		t.Fatalf("NewWhitelist() reload: %v", err)
+	}
+	want := []string{"steam_76561198000000001", "steam_76561198000000002"}
+	if got := reloaded.List(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("List() after reload got %v want %v", got, want)
+	}
+	if !reloaded.Contains("76561198000000001") {
+		t.Fatal("Contains() should ignore the steam_ prefix")
+	}
+
+	if removed, err := reloaded.Remove("76561198000000001"); err != nil || !removed {
+		t.Fatalf("Remove() got %v, %v", removed, err)
+	}
+	if removed, _ := reloaded.Remove("76561198000000001"); removed {
+		t.Fatal("Remove() twice should report false")
+	}
+}
+
+func TestNewWhitelistRejectsBadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "whitelist.json")
+	if err := os.WriteFile(path, []byte(`["steam_76561198000000001", "bob"]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewWhitelist(path); err == nil {
+		t.Fatal("NewWhitelist() with an invalid ID expected error")
+	}
+}
+
+func TestEnforcerEvaluate(t *testing.T) {
+	friend := game.Player{Name: "Friend", SteamID: "steam_76561198000000001"}
+	stranger := game.Player{Name: "Stranger", SteamID: "steam_76561198000000009"}
+	allowed := func(id string) bool { return CanonicalSteamID(id) == friend.SteamID }
+
+	e := NewEnforcer()
+	kick, err := e.Evaluate([]game.Player{friend, stranger}, allowed, false)
+	if err != nil || len(kick) != 0 {
+		t.Fatalf("first sighting got %v, %v; want no kick yet", kick, err)
+	}
+	kick, err = e.Evaluate([]game.Player{friend, stranger}, allowed, false)
+	if err != nil || !reflect.DeepEqual(kick, []game.Player{stranger}) {
+		t.Fatalf("second sighting got %v, %v; want stranger kicked", kick, err)
+	}
+	kick, _ = e.Evaluate([]game.Player{friend, stranger}, allowed, false)
+	if len(kick) != 0 {
+		t.Fatalf("after a kick the count restarts, got %v", kick)
+	}
+}
+
+func TestEnforcerNeedsConsecutiveSightings(t *testing.T) {
+	stranger := game.Player{Name: "Stranger", SteamID: "steam_76561198000000009"}
+	none := func(string) bool { return false }
+
+	e := NewEnforcer()
+	e.Evaluate([]game.Player{stranger}, none, false)
+	e.Evaluate(nil, none, false)
+	if kick, _ := e.Evaluate([]game.Player{stranger}, none, false); len(kick) != 0 {
+		t.Fatalf("sightings with a gap should not add up, got %v", kick)
+	}
+}
+
+func TestEnforcerFailsSafe(t *testing.T) {
+	none := func(string) bool { return false }
+	stranger := game.Player{Name: "Stranger", SteamID: "steam_76561198000000009"}
+
+	tests := []struct {
+		name        string
+		players     []game.Player
+		empty       bool
+		wantErr     error
+		wantTooMany bool
+	}{
+		{
+			name:    "garbled steam id",
+			players: []game.Player{stranger, {Name: "Bob", SteamID: "765611980"}},
+			wantErr: ErrMalformedPlayerList,
+		},
+		{
+			name:    "player still connecting",
+			players: []game.Player{stranger, {Name: "Bob", SteamID: "steam_00000000000000000"}},
+			wantErr: ErrMalformedPlayerList,
+		},
+		{
+			name:    "missing steam id",
+			players: []game.Player{stranger, {Name: "Bob"}},
+			wantErr: ErrMalformedPlayerList,
+		},
+		{
+			name:    "empty whitelist",
+			players: []game.Player{stranger},
+			empty:   true,
+		},
+		{
+			name: "too many at once",
+			players: []game.Player{
+				{Name: "A", SteamID: "steam_76561198000000011"},
+				{Name: "B", SteamID: "steam_76561198000000012"},
+				{Name: "C", SteamID: "steam_76561198000000013"},
+				{Name: "D", SteamID: "steam_76561198000000014"},
+			},
+			wantTooMany: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEnforcer()
+			for i := 0; i < WhitelistConfirmations+1; i++ {
+				kick, err := e.Evaluate(tt.players, none, tt.empty)
+				if len(kick) != 0 {
+					t.Fatalf("check %d kicked %v", i, kick)
+				}
+				var tooMany *TooManyViolatorsError
+				switch {
+				case tt.wantErr != nil && !errors.Is(err, tt.wantErr):
+					t.Fatalf("check %d got err %v want %v", i, err, tt.wantErr)
+				case tt.wantTooMany && !errors.As(err, &tooMany):
+					t.Fatalf("check %d got err %v want TooManyViolatorsError", i, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+// Package palapi is a client for the Palworld dedicated server REST API
+// (RESTAPIEnabled=True in PalWorldSettings.ini).
+package palapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"pikabot/internal/game"
+)
+
+var _ game.GameServer = (*Client)(nil)
+
+// maxErrorBody caps how much of an error response ends up in messages.
+const maxErrorBody = 512
+
+type Client struct {
+	baseURL  string
+	username string
+	password string
+	http     *http.Client
+}
+
+// New returns a client for the API at baseURL, e.g. "http://127.0.0.1:8212".
+// The API uses basic auth with the server's AdminPassword.
+func New(baseURL, username, password string, timeout time.Duration) *Client {
+	return &Client{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		username: username,
+		password: password,
+		http:     &http.Client{Timeout: timeout},
+	}
+}
+
+// StatusError is returned for any non-2xx response.
+type StatusError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	msg := fmt.Sprintf("palworld api %s %s: %d %s", e.Method, e.Path, e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Body != "" {
+		msg += ": " + e.Body
+	}
+	return msg
+}
+
+type Metrics struct {
+	ServerFPS       int     `json:"serverfps"`
+	CurrentPlayers  int     `json:"currentplayernum"`
+	ServerFrameTime float64 `json:"serverframetime"`
+	MaxPlayers      int     `json:"maxplayernum"`
+	UptimeSeconds   int64   `json:"uptime"`
+	Days            int     `json:"days"`
+	BaseCampCount   int     `json:"basecampnum"`
+}
+
+func (m Metrics) Uptime() time.Duration {
+	return time.Duration(m.UptimeSeconds) * time.Second
+}
+
+type infoResponse struct {
+	Version    string `json:"version"`
+	ServerName string `json:"servername"`
+}
+
+type playersResponse struct {
+	Players []struct {
+		Name     string `json:"name"`
+		PlayerID string `json:"playerId"`
+		UserID   string `json:"userId"`
+	} `json:"players"`
+}
+
+func (c *Client) Info(ctx context.Context) (game.ServerInfo, error) {
+	var resp infoResponse
+	if err := c.do(ctx, http.MethodGet, "/v1/api/info", nil, &resp); err != nil {
+		return game.ServerInfo{}, err
+	}
+	return game.ServerInfo{Name: resp.ServerName, Version: resp.Version}, nil
+}
+
+func (c *Client) ShowPlayers(ctx context.Context) ([]game.Player, error) {
+	var resp playersResponse
+	if err := c.do(ctx, http.MethodGet, "/v1/api/players", nil, &resp); err != nil {
+		return nil, err
+	}
+	var players []game.Player
+	for _, p := range resp.Players {
+		players = append(players, game.Player{Name: p.Name, PlayerUID: p.PlayerID, SteamID: p.UserID})
+	}
+	return players, nil
+}
+
+func (c *Client) Metrics(ctx context.Context) (Metrics, error) {
+	var metrics Metrics
+	if err := c.do(ctx, http.MethodGet, "/v1/api/metrics", nil, &metrics); err != nil {
+		return Metrics{}, err
+	}
+	return metrics, nil
+}
+
+func (c *Client) Save(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/v1/api/save", nil, nil)
+}
+
+// Broadcast sends message as-is: unlike RCON, the REST API takes JSON and
+// needs no escaping of spaces.
+func (c *Client) Broadcast(ctx context.Context, message string) error {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return fmt.Errorf("broadcast message is empty")
+	}
+	return c.do(ctx, http.MethodPost, "/v1/api/announce", map[string]string{"message": game.TruncateMessage(message)}, nil)
+}
+
+func (c *Client) KickPlayer(ctx context.Context, steamID string) error {
+	if steamID == "" {
+		return fmt.Errorf("invalid player id %q", steamID)
+	}
+	return c.do(ctx, http.MethodPost, "/v1/api/kick", map[string]string{"userid": steamID}, nil)
+}
+
+func (c *Client) BanPlayer(ctx context.Context, steamID string) error {
+	if steamID == "" {
+		return fmt.Errorf("invalid player id %q", steamID)
+	}
+	return c.do(ctx, http.MethodPost, "/v1/api/ban", map[string]string{"userid": steamID}, nil)
+}
+
+func (c *Client) UnBanPlayer(ctx context.Context, steamID string) error {
+	if steamID == "" {
+		return fmt.Errorf("invalid player id %q", steamID)
+	}
+	return c.do(ctx, http.MethodPost, "/v1/api/unban", map[string]string{"userid": steamID}, nil)
+}
+
+func (c *Client) Shutdown(ctx context.Context, seconds int, message string) error {
+	if seconds < 1 {
+		seconds = 1
+	}
+	body := map[string]any{"waittime": seconds}
+	if message = strings.TrimSpace(message); message != "" {
+		body["message"] = game.TruncateMessage(message)
+	}
+	return c.do(ctx, http.MethodPost, "/v1/api/shutdown", body, nil)
+}
+
+func (c *Client) Close() error {
+	c.http.CloseIdleConnections()
+	return nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("palworld api %s %s: %w", method, path, err)
+	}
+	req.SetBasicAuth(c.username, c.password)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("palworld api %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		text, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return &StatusError{Method: method, Path: path, StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(text))}
+	}
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("palworld api %s %s: decode response: %w", method, path, err)
+	}
+	return nil
+}
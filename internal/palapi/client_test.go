@@ -0,0 +1,199 @@
+package palapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"pikabot/internal/game"
+)
+
+type request struct {
+	Method string
+	Path   string
+	Body   map[string]any
+}
+
+// newTestServer serves canned JSON per path and records every request.
+func newTestServer(t *testing.T, responses map[string]string) (*Client, *[]request) {
+	t.Helper()
+	var requests []request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "admin" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		req := request{Method: r.Method, Path: r.URL.Path}
+		if r.ContentLength > 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req.Body); err != nil {
+				t.Errorf("decode request body: %v", err)
+			}
+		}
+		requests = append(requests, req)
+
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			http.Error(w, "unknown endpoint", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(srv.Close)
+	return New(srv.URL+"/", "admin", "secret", 5*time.Second), &requests
+}
+
+func TestInfoAndPlayers(t *testing.T) {
+	client, _ := newTestServer(t, map[string]string{
+		"/v1/api/info": `{"version":"v0.3.4.56710","servername":"Pal Land","description":"","worldguid":"ABC"}`,
+		"/v1/api/players": `{"players":[
+			{"name":"Alice","accountName":"alice","playerId":"1a2b3c","userId":"steam_76561198000000001","ip":"1.2.3.4","ping":20.5,"location_x":1,"location_y":2,"level":12,"building_count":3},
+			{"name":"Bob","accountName":"bob","playerId":"4d5e6f","userId":"steam_76561198000000002","ip":"5.6.7.8","ping":40,"location_x":0,"location_y":0,"level":1,"building_count":0}
+		]}`,
+	})
+	ctx := context.Background()
+
+	info, err := client.Info(ctx)
+	if err != nil {
+		t.Fatalf("Info(): %v", err)
+	}
+	if info != (game.ServerInfo{Name: "Pal Land", Version: "v0.3.4.56710"}) {
+		t.Fatalf("Info() got %+v", info)
+	}
+
+	players, err := client.ShowPlayers(ctx)
+	if err != nil {
+		t.Fatalf("ShowPlayers(): %v", err)
+	}
+	want := []game.Player{
+		{Name: "Alice", PlayerUID: "1a2b3c", SteamID: "steam_76561198000000001"},
+		{Name: "Bob", PlayerUID: "4d5e6f", SteamID: "steam_76561198000000002"},
+	}
+	if !reflect.DeepEqual(players, want) {
+		t.Fatalf("ShowPlayers() got %+v want %+v", players, want)
+	}
+}
+
+func TestEmptyPlayers(t *testing.T) {
+	client, _ := newTestServer(t, map[string]string{"/v1/api/players": `{"players":[]}`})
+	players, err := client.ShowPlayers(context.Background())
+	if err != nil || len(players) != 0 {
+		t.Fatalf("ShowPlayers() got %+v, %v", players, err)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	client, _ := newTestServer(t, map[string]string{
+		"/v1/api/metrics": `{"serverfps":58,"currentplayernum":3,"serverframetime":17.24,"maxplayernum":32,"uptime":3723,"days":12,"basecampnum":4}`,
+	})
+	metrics, err := client.Metrics(context.Background())
+	if err != nil {
+		t.Fatalf("Metrics(): %v", err)
+	}
+	want := Metrics{ServerFPS: 58, CurrentPlayers: 3, ServerFrameTime: 17.24, MaxPlayers: 32, UptimeSeconds: 3723, Days: 12, BaseCampCount: 4}
+	if metrics != want {
+		t.Fatalf("Metrics() got %+v want %+v", metrics, want)
+	}
+	if metrics.Uptime() != time.Hour+2*time.Minute+3*time.Second {
+		t.Fatalf("Uptime() got %s", metrics.Uptime())
+	}
+}
+
+func TestCommands(t *testing.T) {
+	client, requests := newTestServer(t, map[string]string{
+		"/v1/api/announce": ``,
+		"/v1/api/save":     ``,
+		"/v1/api/kick":     ``,
+		"/v1/api/ban":      ``,
+		"/v1/api/unban":    ``,
+		"/v1/api/shutdown": ``,
+	})
+	ctx := context.Background()
+
+	steps := []struct {
+		name string
+		run  func() error
+		want request
+	}{
+		{
+			name: "broadcast keeps spaces and unicode",
+			run:  func() error { return client.Broadcast(ctx, " Grüße from Matrix 🎉 ") },
+			want: request{Method: http.MethodPost, Path: "/v1/api/announce", Body: map[string]any{"message": "Grüße from Matrix 🎉"}},
+		},
+		{
+			name: "broadcast is capped like over RCON",
+			run:  func() error { return client.Broadcast(ctx, strings.Repeat("a", 198)+" bbbbb") },
+			want: request{Method: http.MethodPost, Path: "/v1/api/announce", Body: map[string]any{"message": strings.Repeat("a", 198) + "…"}},
+		},
+		{
+			name: "save",
+			run:  func() error { return client.Save(ctx) },
+			want: request{Method: http.MethodPost, Path: "/v1/api/save"},
+		},
+		{
+			name: "kick",
+			run:  func() error { return client.KickPlayer(ctx, "steam_1") },
+			want: request{Method: http.MethodPost, Path: "/v1/api/kick", Body: map[string]any{"userid": "steam_1"}},
+		},
+		{
+			name: "ban",
+			run:  func() error { return client.BanPlayer(ctx, "steam_2") },
+			want: request{Method: http.MethodPost, Path: "/v1/api/ban", Body: map[string]any{"userid": "steam_2"}},
+		},
+		{
+			name: "unban",
+			run:  func() error { return client.UnBanPlayer(ctx, "steam_2") },
+			want: request{Method: http.MethodPost, Path: "/v1/api/unban", Body: map[string]any{"userid": "steam_2"}},
+		},
+		{
+			name: "shutdown",
+			run:  func() error { return client.Shutdown(ctx, 30, "bye all") },
+			want: request{Method: http.MethodPost, Path: "/v1/api/shutdown", Body: map[string]any{"waittime": float64(30), "message": "bye all"}},
+		},
+	}
+
+	for _, step := range steps {
+		before := len(*requests)
+		if err := step.run(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if len(*requests) != before+1 {
+			t.Fatalf("%s: sent %d requests, want 1", step.name, len(*requests)-before)
+		}
+		if got := (*requests)[before]; !reflect.DeepEqual(got, step.want) {
+			t.Fatalf("%s: request got %+v want %+v", step.name, got, step.want)
+		}
+	}
+
+	if err := client.Broadcast(ctx, "  "); err == nil {
+		t.Fatal("Broadcast() of blank message expected error")
+	}
+	if err := client.KickPlayer(ctx, ""); err == nil {
+		t.Fatal("KickPlayer() without id expected error")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	client, _ := newTestServer(t, map[string]string{"/v1/api/info": `not json`})
+	ctx := context.Background()
+
+	var statusErr *StatusError
+	if _, err := client.Metrics(ctx); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("Metrics() on missing endpoint got %v, want 404 StatusError", err)
+	}
+	if _, err := client.Info(ctx); err == nil {
+		t.Fatal("Info() with invalid JSON expected error")
+	}
+
+	wrongPass := New(client.baseURL, "admin", "wrong", time.Second)
+	if _, err := wrongPass.Info(ctx); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Info() with wrong password got %v, want 401 StatusError", err)
+	}
+}
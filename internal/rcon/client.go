@@ -1,6 +1,7 @@
 package rcon
 
 import (
+	"bufio"
 	"context"
 	"encoding/binary"
 	"errors"
@@ -9,6 +10,7 @@ import (
 	"net"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -18,62 +20,214 @@ const (
 	packetTypeAuth          = 3
 )
 
+const authPacketID = 1
+
+var (
+	errAuthFailed      = errors.New("rcon auth failed")
+	errCommandRejected = errors.New("rcon command rejected")
+	errSendFailed      = errors.New("send command packet")
+)
+
+// readOnlyCommands are safe to send twice. Anything else may already have
+// run when the response is lost, so it is only resent if sending failed.
+var readOnlyCommands = map[string]bool{
+	"ShowPlayers": true,
+	"Info":        true,
+}
+
+// defaultIdleProbe is how long a session may sit unused before a command
+// with effects is preceded by a read-only probe. Servers drop idle
+// connections without notice, and a command lost on such a session cannot
+// be resent.
+const (
+	defaultIdleProbe = 5 * time.Second
+	probeCommand     = "Info"
+)
+
+type State int
+
+const (
+	Disconnected State = iota
+	Connected
+)
+
+func (s State) String() string {
+	switch s {
+	case Disconnected:
+		return "disconnected"
+	case Connected:
+		return "connected"
+	default:
+		return "unknown"
+	}
+}
+
 type Client struct {
 	host    string
 	port    int
 	pass    string
 	timeout time.Duration
+	// idleProbe is the idle time after which a session is probed before
+	// a command with effects is sent on it.
+	idleProbe time.Duration
+
+	mu       sync.Mutex
+	conn     net.Conn
+	reader   *bufio.Reader
+	nextID   int32
+	lastUsed time.Time
+	lastErr  error
 }
 
 func New(host string, port int, pass string, timeout time.Duration) *Client {
 	if timeout <= 0 {
 		timeout = 5 * time.Second
 	}
-	return &Client{host: host, port: port, pass: pass, timeout: timeout}
+	return &Client{host: host, port: port, pass: pass, timeout: timeout, idleProbe: defaultIdleProbe}
 }
 
-func (c *Client) ShowPlayers(ctx context.Context) ([]string, error) {
-	response, err := c.execute(ctx, "ShowPlayers")
-	if err != nil {
-		return nil, err
+func (c *Client) State() State {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return Disconnected
 	}
-	return ParseShowPlayers(response), nil
+	return Connected
+}
+
+func (c *Client) LastError() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastErr
 }
 
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.disconnect()
+}
+
+// execute runs command over the shared session. Commands are serialized by
+// c.mu; a command that fails on an existing connection is retried once on a
+// freshly dialed and authenticated one. Commands that cannot be retried are
+// only sent on an idle session after a read-only probe got an answer.
 func (c *Client) execute(ctx context.Context, command string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn != nil && !readOnly(command) && time.Since(c.lastUsed) >= c.idleProbe {
+		// A failed probe drops the dead session, so the command goes out on
+		// a fresh one below. The probe error itself is not the caller's.
+		_, _ = c.executeLocked(ctx, probeCommand)
+	}
+
+	reused := c.conn != nil
+	response, err := c.executeLocked(ctx, command)
+	if err != nil && reused && ctx.Err() == nil && retryable(command, err) {
+		response, err = c.executeLocked(ctx, command)
+	}
+	c.lastErr = err
+	return response, err
+}
+
+// retryable reports whether a command that failed on a reused session may
+// be sent again on a fresh one.
+func retryable(command string, err error) bool {
+	if errors.Is(err, errAuthFailed) || errors.Is(err, errCommandRejected) {
+		return false
+	}
+	return readOnly(command) || errors.Is(err, errSendFailed)
+}
+
+func readOnly(command string) bool {
+	name, _, _ := strings.Cut(command, " ")
+	return readOnlyCommands[name]
+}
+
+func (c *Client) executeLocked(ctx context.Context, command string) (string, error) {
+	if c.conn == nil {
+		if err := c.connect(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	response, err := c.roundTrip(ctx, command)
+	if err != nil {
+		_ = c.disconnect()
+		return "", err
+	}
+	c.lastUsed = time.Now()
+	return response, nil
+}
+
+func (c *Client) connect(ctx context.Context) error {
 	addr := net.JoinHostPort(c.host, strconv.Itoa(c.port))
 	dialer := &net.Dialer{Timeout: c.timeout}
 	conn, err := dialer.DialContext(ctx, "tcp", addr)
 	if err != nil {
-		return "", fmt.Errorf("dial rcon %s: %w", addr, err)
+		return fmt.Errorf("dial rcon %s: %w", addr, err)
 	}
-	defer conn.Close()
 
-	deadline := time.Now().Add(c.timeout)
-	if dl, ok := ctx.Deadline(); ok && dl.Before(deadline) {
-		deadline = dl
+	if err := conn.SetDeadline(c.deadline(ctx)); err != nil {
+		conn.Close()
+		return fmt.Errorf("set deadline: %w", err)
 	}
-	if err := conn.SetDeadline(deadline); err != nil {
-		return "", fmt.Errorf("set deadline: %w", err)
+
+	reader := bufio.NewReader(conn)
+	if err := writePacket(conn, authPacketID, packetTypeAuth, c.pass); err != nil {
+		conn.Close()
+		return fmt.Errorf("send auth packet: %w", err)
+	}
+	if err := readAuthResponse(reader); err != nil {
+		conn.Close()
+		return err
 	}
 
-	if err := writePacket(conn, 1, packetTypeAuth, c.pass); err != nil {
-		return "", fmt.Errorf("send auth packet: %w", err)
+	c.conn = conn
+	c.reader = reader
+	c.nextID = authPacketID
+	return nil
+}
+
+func (c *Client) disconnect() error {
+	if c.conn == nil {
+		return nil
 	}
-	if err := readAuthResponse(conn); err != nil {
-		return "", err
+	err := c.conn.Close()
+	c.conn = nil
+	c.reader = nil
+	return err
+}
+
+func (c *Client) roundTrip(ctx context.Context, command string) (string, error) {
+	if err := c.conn.SetDeadline(c.deadline(ctx)); err != nil {
+		return "", fmt.Errorf("set deadline: %w", err)
 	}
 
-	if err := writePacket(conn, 2, packetTypeExecCommand, command); err != nil {
-		return "", fmt.Errorf("send command packet: %w", err)
+	c.nextID++
+	if c.nextID <= authPacketID {
+		c.nextID = authPacketID + 1
 	}
+	requestID := c.nextID
 
-	first, err := readPacket(conn)
-	if err != nil {
-		return "", fmt.Errorf("read command response: %w", err)
+	if err := writePacket(c.conn, requestID, packetTypeExecCommand, command); err != nil {
+		return "", fmt.Errorf("%w: %w", errSendFailed, err)
 	}
-	if first.ID == -1 {
-		return "", errors.New("rcon command rejected")
+
+	var first packet
+	for {
+		pkt, err := readPacket(c.reader)
+		if err != nil {
+			return "", fmt.Errorf("read command response: %w", err)
+		}
+		if pkt.ID == -1 {
+			return "", errCommandRejected
+		}
+		// Late packets from an earlier command that timed out are dropped.
+		if !isStale(pkt.ID, requestID) {
+			first = pkt
+			break
+		}
 	}
 
 	builder := strings.Builder{}
@@ -81,15 +235,27 @@ func (c *Client) execute(ctx context.Context, command string) (string, error) {
 		builder.WriteString(first.Body)
 	}
 
-	_ = conn.SetReadDeadline(time.Now().Add(150 * time.Millisecond))
+	// Palworld may split long responses over several packets without any
+	// terminator, so keep reading until the server goes quiet. Peeking the
+	// length prefix first ensures a timeout never leaves a half-read packet
+	// behind on the shared connection.
 	for {
-		next, err := readPacket(conn)
-		if err != nil {
-			if isTimeout(err) {
-				break
+		_ = c.conn.SetReadDeadline(time.Now().Add(150 * time.Millisecond))
+		if _, err := c.reader.Peek(4); err != nil {
+			if !isTimeout(err) {
+				// The response is complete but the session is not reusable.
+				_ = c.disconnect()
 			}
+			break
+		}
+		_ = c.conn.SetReadDeadline(c.deadline(ctx))
+		next, err := readPacket(c.reader)
+		if err != nil {
 			return "", fmt.Errorf("read additional response packet: %w", err)
 		}
+		if isStale(next.ID, requestID) {
+			continue
+		}
 		if next.Type == packetTypeResponseValue {
 			if builder.Len() > 0 && !strings.HasSuffix(builder.String(), "\n") {
 				builder.WriteString("\n")
@@ -101,9 +267,23 @@ func (c *Client) execute(ctx context.Context, command string) (string, error) {
 	return builder.String(), nil
 }
 
-func readAuthResponse(conn net.Conn) error {
+// isStale reports whether a packet answers an earlier request on the same
+// session. Servers that always reply with ID 0 are treated as current.
+func isStale(packetID, requestID int32) bool {
+	return packetID > authPacketID && packetID < requestID
+}
+
+func (c *Client) deadline(ctx context.Context) time.Time {
+	deadline := time.Now().Add(c.timeout)
+	if dl, ok := ctx.Deadline(); ok && dl.Before(deadline) {
+		deadline = dl
+	}
+	return deadline
+}
+
+func readAuthResponse(r io.Reader) error {
 	for i := 0; i < 3; i++ {
-		pkt, err := readPacket(conn)
+		pkt, err := readPacket(r)
 		if err != nil {
 			return fmt.Errorf("read auth response: %w", err)
 		}
@@ -111,11 +291,11 @@ func readAuthResponse(conn net.Conn) error {
 			continue
 		}
 		if pkt.ID == -1 {
-			return errors.New("rcon auth failed")
+			return errAuthFailed
 		}
 		return nil
 	}
-	return errors.New("rcon auth failed: no auth response packet")
+	return fmt.Errorf("%w: no auth response packet", errAuthFailed)
 }
 
 type packet struct {
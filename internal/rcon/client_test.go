@@ -0,0 +1,207 @@
+package rcon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakeServer struct {
+	ln       net.Listener
+	pass     string
+	accepts  atomic.Int32
+	executed atomic.Int32
+	response string
+	// closeAfter closes each connection after this many commands when > 0,
+	// waiting closeDelay first so the client believes the session is idle.
+	closeAfter int
+	closeDelay time.Duration
+}
+
+func newFakeServer(t *testing.T, pass, response string) *fakeServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeServer{ln: ln, pass: pass, response: response}
+	t.Cleanup(func() { ln.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *fakeServer) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.accepts.Add(1)
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeServer) handle(conn net.Conn) {
+	defer conn.Close()
+	commands := 0
+	for {
+		pkt, err := readPacket(conn)
+		if err != nil {
+			return
+		}
+		switch pkt.Type {
+		case packetTypeAuth:
+			id := pkt.ID
+			if pkt.Body != s.pass {
+				id = -1
+			}
+			_ = writePacket(conn, id, packetTypeExecCommand, "")
+		case packetTypeExecCommand:
+			s.executed.Add(1)
+			_ = writePacket(conn, pkt.ID, packetTypeResponseValue, s.response)
+			commands++
+			if s.closeAfter > 0 && commands >= s.closeAfter {
+				time.Sleep(s.closeDelay)
+				return
+			}
+		}
+	}
+}
+
+func (s *fakeServer) client(pass string) *Client {
+	addr := s.ln.Addr().(*net.TCPAddr)
+	return New("127.0.0.1", addr.Port, pass, time.Second)
+}
+
+func TestClientReusesSession(t *testing.T) {
+	srv := newFakeServer(t, "secret", "name,playeruid,steamid\nAlice,uid1,steam1\n")
+	c := srv.client("secret")
+	defer c.Close()
+
+	for i := 0; i < 3; i++ {
+		players, err := c.ShowPlayers(context.Background())
+		if err != nil {
+			t.Fatalf("ShowPlayers() call %d: %v", i, err)
+		}
+		if len(players) != 1 {
+			t.Fatalf("ShowPlayers() call %d got %v", i, players)
+		}
+	}
+	if got := srv.accepts.Load(); got != 1 {
+		t.Fatalf("server accepted %d connections, want 1", got)
+	}
+	if c.State() != Connected {
+		t.Fatalf("State() got %v want %v", c.State(), Connected)
+	}
+}
+
+func TestClientReconnectsAfterDrop(t *testing.T) {
+	srv := newFakeServer(t, "secret", "name,playeruid,steamid\n")
+	srv.closeAfter = 1
+	srv.closeDelay = 300 * time.Millisecond
+	c := srv.client("secret")
+	defer c.Close()
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.ShowPlayers(context.Background()); err != nil {
+			t.Fatalf("ShowPlayers() call %d: %v", i, err)
+		}
+		time.Sleep(2 * srv.closeDelay)
+	}
+	if got := srv.accepts.Load(); got != 3 {
+		t.Fatalf("server accepted %d connections, want 3", got)
+	}
+}
+
+func TestClientDoesNotResendCommandsWithEffects(t *testing.T) {
+	srv := newFakeServer(t, "secret", "Complete Save")
+	srv.closeAfter = 1
+	srv.closeDelay = 300 * time.Millisecond
+	c := srv.client("secret")
+	c.idleProbe = time.Hour
+	defer c.Close()
+
+	if err := c.Save(context.Background()); err != nil {
+		t.Fatalf("first Save(): %v", err)
+	}
+	time.Sleep(2 * srv.closeDelay)
+
+	// The session is dead, but the write still succeeds locally; the server
+	// could have run the command, so it must not be sent again.
+	if err := c.Save(context.Background()); err == nil {
+		t.Fatal("Save() on a dropped session expected error")
+	}
+	if err := c.Save(context.Background()); err != nil {
+		t.Fatalf("Save() after reconnect: %v", err)
+	}
+	if got := srv.executed.Load(); got != 2 {
+		t.Fatalf("server executed %d commands, want 2", got)
+	}
+}
+
+func TestClientProbesIdleSessionBeforeCommandsWithEffects(t *testing.T) {
+	srv := newFakeServer(t, "secret", "Complete Save")
+	srv.closeAfter = 1
+	srv.closeDelay = 300 * time.Millisecond
+	c := srv.client("secret")
+	c.idleProbe = 100 * time.Millisecond
+	defer c.Close()
+
+	if err := c.Save(context.Background()); err != nil {
+		t.Fatalf("first Save(): %v", err)
+	}
+	time.Sleep(2 * srv.closeDelay)
+
+	// The probe finds the dropped session, so Save goes out on a new one.
+	if err := c.Save(context.Background()); err != nil {
+		t.Fatalf("Save() on an idle dropped session: %v", err)
+	}
+	if got := srv.executed.Load(); got != 2 {
+		t.Fatalf("server executed %d commands, want 2", got)
+	}
+	if got := srv.accepts.Load(); got != 2 {
+		t.Fatalf("server accepted %d connections, want 2", got)
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	lost := errors.New("read command response: EOF")
+	tests := []struct {
+		command string
+		err     error
+		want    bool
+	}{
+		{command: "ShowPlayers", err: lost, want: true},
+		{command: "Info", err: lost, want: true},
+		{command: "Save", err: lost, want: false},
+		{command: "Broadcast hi", err: lost, want: false},
+		{command: "KickPlayer steam_1", err: fmt.Errorf("%w: broken pipe", errSendFailed), want: true},
+		{command: "ShowPlayers", err: errAuthFailed, want: false},
+		{command: "Info", err: errCommandRejected, want: false},
+	}
+	for _, tt := range tests {
+		if got := retryable(tt.command, tt.err); got != tt.want {
+			t.Fatalf("retryable(%q, %v) got %v want %v", tt.command, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestClientAuthFailure(t *testing.T) {
+	srv := newFakeServer(t, "secret", "")
+	c := srv.client("wrong")
+	defer c.Close()
+
+	if _, err := c.ShowPlayers(context.Background()); err == nil {
+		t.Fatal("ShowPlayers() expected auth error")
+	}
+	if c.State() != Disconnected {
+		t.Fatalf("State() got %v want %v", c.State(), Disconnected)
+	}
+	if c.LastError() == nil {
+		t.Fatal("LastError() expected error after failed auth")
+	}
+}
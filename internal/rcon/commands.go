@@ -0,0 +1,111 @@
+package rcon
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"pikabot/internal/game"
+)
+
+var _ game.GameServer = (*Client)(nil)
+
+func (c *Client) ShowPlayers(ctx context.Context) ([]Player, error) {
+	response, err := c.execute(ctx, "ShowPlayers")
+	if err != nil {
+		return nil, err
+	}
+	return ParseShowPlayers(response), nil
+}
+
+func (c *Client) Info(ctx context.Context) (ServerInfo, error) {
+	response, err := c.execute(ctx, "Info")
+	if err != nil {
+		return ServerInfo{}, err
+	}
+	return ParseInfo(response)
+}
+
+func (c *Client) Save(ctx context.Context) error {
+	response, err := c.execute(ctx, "Save")
+	if err != nil {
+		return err
+	}
+	return ParseSave(response)
+}
+
+func (c *Client) Broadcast(ctx context.Context, message string) error {
+	message = encodeMessage(message)
+	if message == "" {
+		return fmt.Errorf("broadcast message is empty")
+	}
+	response, err := c.execute(ctx, "Broadcast "+message)
+	if err != nil {
+		return err
+	}
+	return ParseBroadcast(response)
+}
+
+func (c *Client) KickPlayer(ctx context.Context, steamID string) error {
+	if err := validateID(steamID); err != nil {
+		return err
+	}
+	response, err := c.execute(ctx, "KickPlayer "+steamID)
+	if err != nil {
+		return err
+	}
+	return ParseKickPlayer(response)
+}
+
+func (c *Client) BanPlayer(ctx context.Context, steamID string) error {
+	if err := validateID(steamID); err != nil {
+		return err
+	}
+	response, err := c.execute(ctx, "BanPlayer "+steamID)
+	if err != nil {
+		return err
+	}
+	return ParseBanPlayer(response)
+}
+
+func (c *Client) UnBanPlayer(ctx context.Context, steamID string) error {
+	if err := validateID(steamID); err != nil {
+		return err
+	}
+	response, err := c.execute(ctx, "UnBanPlayer "+steamID)
+	if err != nil {
+		return err
+	}
+	return ParseUnBanPlayer(response)
+}
+
+func (c *Client) Shutdown(ctx context.Context, seconds int, message string) error {
+	if seconds < 1 {
+		seconds = 1
+	}
+	command := "Shutdown " + strconv.Itoa(seconds)
+	if message = encodeMessage(message); message != "" {
+		command += " " + message
+	}
+	response, err := c.execute(ctx, command)
+	if err != nil {
+		return err
+	}
+	return ParseShutdown(response)
+}
+
+func (c *Client) DoExit(ctx context.Context) error {
+	response, err := c.execute(ctx, "DoExit")
+	if err != nil {
+		return err
+	}
+	return ParseDoExit(response)
+}
+
+func validateID(id string) error {
+	if id == "" || strings.ContainsAny(id, " \t\r\n") {
+		return fmt.Errorf("invalid player id %q", id)
+	}
+	return nil
+}
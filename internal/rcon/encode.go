@@ -0,0 +1,79 @@
+package rcon
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"pikabot/internal/game"
+)
+
+// MaxMessageLength caps broadcast text, in characters, so a long chat
+// message cannot overflow the RCON packet or the in-game message box. It
+// is shared with the REST backend.
+const MaxMessageLength = game.MaxMessageLength
+
+const (
+	// messageSpace stands in for whitespace: Palworld splits command
+	// arguments on ASCII spaces and drops everything after the first word,
+	// but shows a no-break space like a normal one.
+	messageSpace = '\u00a0'
+	ellipsis     = '…'
+)
+
+// encodeMessage makes arbitrary text safe to pass as a single Broadcast or
+// Shutdown argument:
+//   - every run of Unicode whitespace, including newlines and tabs, becomes
+//     one no-break space, and leading/trailing whitespace is dropped
+//   - other control characters (NUL ends the RCON body early) are removed
+//   - invalid UTF-8 is replaced with U+FFFD instead of reaching the server
+//   - text longer than MaxMessageLength characters is cut with an ellipsis
+//
+// Everything else, including non-Latin scripts and emoji, is sent as UTF-8.
+func encodeMessage(message string) string {
+	var b strings.Builder
+	pendingSpace := false
+	length := 0
+	for i := 0; i < len(message); {
+		r, size := utf8.DecodeRuneInString(message[i:])
+		i += size
+
+		switch {
+		case unicode.IsSpace(r):
+			pendingSpace = b.Len() > 0
+			continue
+		case unicode.IsControl(r):
+			continue
+		}
+
+		needed := 1
+		if pendingSpace {
+			needed = 2
+		}
+		if length+needed > MaxMessageLength {
+			return truncate(b.String(), length)
+		}
+		if pendingSpace {
+			b.WriteRune(messageSpace)
+			pendingSpace = false
+		}
+		// DecodeRuneInString reports invalid bytes as RuneError; writing it
+		// back produces a proper U+FFFD.
+		b.WriteRune(r)
+		length += needed
+	}
+	return b.String()
+}
+
+// truncate replaces the last character of an over-long message with an
+// ellipsis, trimming a trailing space so the cut does not look accidental.
+func truncate(encoded string, length int) string {
+	runes := []rune(encoded)
+	if length >= MaxMessageLength {
+		runes = runes[:MaxMessageLength-1]
+	}
+	for len(runes) > 0 && runes[len(runes)-1] == messageSpace {
+		runes = runes[:len(runes)-1]
+	}
+	return string(append(runes, ellipsis))
+}
@@ -0,0 +1,80 @@
+package rcon
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestEncodeMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{name: "empty", message: "", want: ""},
+		{name: "only whitespace", message: " \t\n ", want: ""},
+		{name: "single word", message: "hello", want: "hello"},
+		{name: "spaces become no-break spaces", message: "server restarts soon", want: "server\u00a0restarts\u00a0soon"},
+		{name: "whitespace runs collapse", message: "  a \t\n b  ", want: "a\u00a0b"},
+		{name: "unicode whitespace", message: "a\u3000b\u2003c\u00a0d", want: "a\u00a0b\u00a0c\u00a0d"},
+		{name: "control characters removed", message: "a\x00b\x07c\x1b[0m", want: "abc[0m"},
+		{name: "non-latin scripts kept", message: "こんにちは 世界", want: "こんにちは\u00a0世界"},
+		{name: "accents and emoji kept", message: "Grüße 🎉 from Zoë", want: "Grüße\u00a0🎉\u00a0from\u00a0Zoë"},
+		{name: "underscores untouched", message: "snake_case name", want: "snake_case\u00a0name"},
+		{name: "invalid utf-8 replaced", message: "a\xffb", want: "a�b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := encodeMessage(tt.message)
+			if got != tt.want {
+				t.Fatalf("encodeMessage(%q) got %q want %q", tt.message, got, tt.want)
+			}
+			if strings.ContainsAny(got, " \t\r\n\x00") {
+				t.Fatalf("encodeMessage(%q) = %q still contains separators", tt.message, got)
+			}
+		})
+	}
+}
+
+func TestEncodeMessageTruncates(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{
+			name:    "exact limit kept",
+			message: strings.Repeat("x", MaxMessageLength),
+			want:    strings.Repeat("x", MaxMessageLength),
+		},
+		{
+			name:    "one over the limit",
+			message: strings.Repeat("x", MaxMessageLength+1),
+			want:    strings.Repeat("x", MaxMessageLength-1) + "…",
+		},
+		{
+			name:    "multi-byte characters count once",
+			message: strings.Repeat("é", MaxMessageLength+10),
+			want:    strings.Repeat("é", MaxMessageLength-1) + "…",
+		},
+		{
+			name:    "no dangling space before ellipsis",
+			message: strings.Repeat("x", MaxMessageLength-2) + " yy",
+			want:    strings.Repeat("x", MaxMessageLength-2) + "…",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := encodeMessage(tt.message)
+			if got != tt.want {
+				t.Fatalf("encodeMessage() got %q (%d chars) want %q", got, utf8.RuneCountInString(got), tt.want)
+			}
+			if n := utf8.RuneCountInString(got); n > MaxMessageLength {
+				t.Fatalf("encodeMessage() returned %d chars, limit %d", n, MaxMessageLength)
+			}
+		})
+	}
+}
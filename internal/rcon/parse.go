@@ -1,12 +1,30 @@
 package rcon
 
-import "strings"
+import (
+	"fmt"
+	"strings"
 
-func ParseShowPlayers(response string) []string {
+	"pikabot/internal/game"
+)
+
+type Player = game.Player
+
+const (
+	columnName      = "name"
+	columnPlayerUID = "playeruid"
+	columnSteamID   = "steamid"
+)
+
+var defaultColumns = []string{columnName, columnPlayerUID, columnSteamID}
+
+// ParseShowPlayers maps each row onto the columns named in the header line,
+// so reordered or additional columns are handled. Rows with more fields than
+// the header are assumed to have commas inside the name.
+func ParseShowPlayers(response string) []Player {
 	lines := strings.Split(response, "\n")
 	clean := make([]string, 0, len(lines))
 	for _, line := range lines {
-		line = strings.TrimSpace(line)
+		line = strings.TrimSpace(strings.Trim(line, "\x00\ufeff"))
 		if line == "" {
 			continue
 		}
@@ -16,16 +34,152 @@ func ParseShowPlayers(response string) []string {
 		return nil
 	}
 
-	players := make([]string, 0, len(clean)-1)
+	columns := parseHeader(clean[0])
+	nameIdx := indexOf(columns, columnName)
+
+	players := make([]Player, 0, len(clean)-1)
 	for _, line := range clean[1:] {
-		parts := strings.Split(line, ",")
-		if len(parts) == 0 {
-			continue
+		fields := splitRow(line, len(columns), nameIdx)
+		player := Player{}
+		for i, column := range columns {
+			if i >= len(fields) {
+				break
+			}
+			value := strings.TrimSpace(fields[i])
+			switch column {
+			case columnName:
+				player.Name = value
+			case columnPlayerUID:
+				player.PlayerUID = value
+			case columnSteamID:
+				player.SteamID = value
+			}
 		}
-		name := strings.TrimSpace(parts[0])
-		if name != "" {
-			players = append(players, name)
+		if player.Name != "" {
+			players = append(players, player)
 		}
 	}
 	return players
 }
+
+func Names(players []Player) []string {
+	return game.Names(players)
+}
+
+func parseHeader(line string) []string {
+	parts := strings.Split(line, ",")
+	columns := make([]string, 0, len(parts))
+	for _, part := range parts {
+		columns = append(columns, strings.ToLower(strings.TrimSpace(part)))
+	}
+	if indexOf(columns, columnName) < 0 {
+		return defaultColumns
+	}
+	return columns
+}
+
+func splitRow(line string, columns, nameIdx int) []string {
+	fields := strings.Split(line, ",")
+	extra := len(fields) - columns
+	if extra <= 0 {
+		return fields
+	}
+	merged := make([]string, 0, columns)
+	merged = append(merged, fields[:nameIdx]...)
+	merged = append(merged, strings.Join(fields[nameIdx:nameIdx+extra+1], ","))
+	merged = append(merged, fields[nameIdx+extra+1:]...)
+	return merged
+}
+
+func indexOf(values []string, want string) int {
+	for i, value := range values {
+		if value == want {
+			return i
+		}
+	}
+	return -1
+}
+
+type ServerInfo = game.ServerInfo
+
+func ParseInfo(response string) (ServerInfo, error) {
+	line := firstLine(response)
+	open := strings.Index(line, "[")
+	end := strings.Index(line, "]")
+	if open < 0 || end < open {
+		return ServerInfo{}, unexpectedResponse("Info", response)
+	}
+	info := ServerInfo{
+		Version: strings.TrimSpace(line[open+1 : end]),
+		Name:    strings.TrimSpace(line[end+1:]),
+	}
+	if info.Version == "" {
+		return ServerInfo{}, unexpectedResponse("Info", response)
+	}
+	return info, nil
+}
+
+func ParseSave(response string) error {
+	return parseAck("Save", response, "Complete Save")
+}
+
+func ParseBroadcast(response string) error {
+	return parseAck("Broadcast", response, "Broadcasted:")
+}
+
+func ParseKickPlayer(response string) error {
+	return parseAck("KickPlayer", response, "Kicked:")
+}
+
+func ParseBanPlayer(response string) error {
+	// Palworld spells the acknowledgement "Baned".
+	return parseAck("BanPlayer", response, "Baned:", "Banned:")
+}
+
+func ParseUnBanPlayer(response string) error {
+	return parseAck("UnBanPlayer", response, "UnBan:", "Unbanned:")
+}
+
+func ParseShutdown(response string) error {
+	return parseAck("Shutdown", response, "The server will shut down")
+}
+
+func ParseDoExit(response string) error {
+	return parseAck("DoExit", response, "Shutdown server")
+}
+
+type UnexpectedResponseError struct {
+	Command  string
+	Response string
+}
+
+func (e *UnexpectedResponseError) Error() string {
+	if e.Response == "" {
+		return fmt.Sprintf("rcon %s: empty response", e.Command)
+	}
+	return fmt.Sprintf("rcon %s: unexpected response %q", e.Command, e.Response)
+}
+
+func unexpectedResponse(command, response string) error {
+	return &UnexpectedResponseError{Command: command, Response: strings.TrimSpace(response)}
+}
+
+func parseAck(command, response string, prefixes ...string) error {
+	line := strings.ToLower(firstLine(response))
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(line, strings.ToLower(prefix)) {
+			return nil
+		}
+	}
+	return unexpectedResponse(command, response)
+}
+
+func firstLine(response string) string {
+	for _, line := range strings.Split(response, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			return line
+		}
+	}
+	return ""
+}
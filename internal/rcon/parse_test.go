@@ -9,7 +9,7 @@ func TestParseShowPlayers(t *testing.T) {
 	tests := []struct {
 		name     string
 		response string
-		want     []string
+		want     []Player
 	}{
 		{
 			name:     "header only",
@@ -24,12 +24,48 @@ func TestParseShowPlayers(t *testing.T) {
 		{
 			name:     "multiple players",
 			response: "name,playeruid,steamid\nAlice,uid1,steam1\nBob,uid2,steam2\n",
-			want:     []string{"Alice", "Bob"},
+			want: []Player{
+				{Name: "Alice", PlayerUID: "uid1", SteamID: "steam1"},
+				{Name: "Bob", PlayerUID: "uid2", SteamID: "steam2"},
+			},
 		},
 		{
 			name:     "ignores empty player name",
 			response: "name,playeruid,steamid\n,uid1,steam1\nCharlie,uid2,steam2\n",
-			want:     []string{"Charlie"},
+			want:     []Player{{Name: "Charlie", PlayerUID: "uid2", SteamID: "steam2"}},
+		},
+		{
+			name:     "reordered columns",
+			response: "steamid,name,playeruid\nsteam1,Alice,uid1\n",
+			want:     []Player{{Name: "Alice", PlayerUID: "uid1", SteamID: "steam1"}},
+		},
+		{
+			name:     "name containing commas",
+			response: "name,playeruid,steamid\nAlice, the Brave,uid1,steam1\n",
+			want:     []Player{{Name: "Alice, the Brave", PlayerUID: "uid1", SteamID: "steam1"}},
+		},
+		{
+			name:     "name containing commas in middle column",
+			response: "playeruid,name,steamid\nuid1,a,b,c,steam1\n",
+			want:     []Player{{Name: "a,b,c", PlayerUID: "uid1", SteamID: "steam1"}},
+		},
+		{
+			name:     "non-ascii names",
+			response: "name,playeruid,steamid\r\nピカチュウ,uid1,steam1\r\nZoë 🐉,uid2,steam2\r\n",
+			want: []Player{
+				{Name: "ピカチュウ", PlayerUID: "uid1", SteamID: "steam1"},
+				{Name: "Zoë 🐉", PlayerUID: "uid2", SteamID: "steam2"},
+			},
+		},
+		{
+			name:     "extra unknown column",
+			response: "name,playeruid,steamid,level\nAlice,uid1,steam1,42\n",
+			want:     []Player{{Name: "Alice", PlayerUID: "uid1", SteamID: "steam1"}},
+		},
+		{
+			name:     "trailing nul bytes",
+			response: "name,playeruid,steamid\nAlice,uid1,steam1\x00\x00",
+			want:     []Player{{Name: "Alice", PlayerUID: "uid1", SteamID: "steam1"}},
 		},
 	}
 
@@ -42,3 +78,77 @@ func TestParseShowPlayers(t *testing.T) {
 		})
 	}
 }
+
+func TestParseInfo(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     ServerInfo
+		wantErr  bool
+	}{
+		{
+			name:     "default server",
+			response: "Welcome to Pal Server[v0.1.5.1] Default Palworld Server\n",
+			want:     ServerInfo{Name: "Default Palworld Server", Version: "v0.1.5.1"},
+		},
+		{
+			name:     "leading blank line and spaces",
+			response: "\n  Welcome to Pal Server[v0.3.4.56710]  Pika Land \n",
+			want:     ServerInfo{Name: "Pika Land", Version: "v0.3.4.56710"},
+		},
+		{
+			name:     "empty name",
+			response: "Welcome to Pal Server[v0.2.0.0]",
+			want:     ServerInfo{Name: "", Version: "v0.2.0.0"},
+		},
+		{name: "empty", response: "", wantErr: true},
+		{name: "no version", response: "Welcome to Pal Server", wantErr: true},
+		{name: "empty version", response: "Welcome to Pal Server[] Name", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseInfo(tt.response)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseInfo() err %v wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("ParseInfo() got %+v want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAcknowledgements(t *testing.T) {
+	tests := []struct {
+		name     string
+		parse    func(string) error
+		response string
+		wantErr  bool
+	}{
+		{name: "save", parse: ParseSave, response: "Complete Save\n"},
+		{name: "save failure", parse: ParseSave, response: "Failed Save", wantErr: true},
+		{name: "broadcast", parse: ParseBroadcast, response: "Broadcasted: hello_world"},
+		{name: "broadcast empty", parse: ParseBroadcast, response: "", wantErr: true},
+		{name: "kick", parse: ParseKickPlayer, response: "Kicked: 76561198000000000"},
+		{name: "kick failure", parse: ParseKickPlayer, response: "Failed to Kick: 76561198000000000", wantErr: true},
+		{name: "ban palworld spelling", parse: ParseBanPlayer, response: "Baned: 76561198000000000"},
+		{name: "ban correct spelling", parse: ParseBanPlayer, response: "Banned: 76561198000000000"},
+		{name: "ban failure", parse: ParseBanPlayer, response: "Failed to Ban: 76561198000000000", wantErr: true},
+		{name: "unban", parse: ParseUnBanPlayer, response: "UnBan: 76561198000000000"},
+		{name: "unban failure", parse: ParseUnBanPlayer, response: "Failed to UnBan", wantErr: true},
+		{name: "shutdown", parse: ParseShutdown, response: "The server will shut down in 30 seconds. Please prepare to exit the game."},
+		{name: "shutdown unexpected", parse: ParseShutdown, response: "Unknown command", wantErr: true},
+		{name: "exit", parse: ParseDoExit, response: "Shutdown server"},
+		{name: "exit unexpected", parse: ParseDoExit, response: "nope", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.parse(tt.response)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parse(%q) err %v wantErr %v", tt.response, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package restartpolicy
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+type Policy struct {
+	// MaxUptime triggers a restart once the container has run this long.
+	// Zero disables the uptime rule.
+	MaxUptime time.Duration
+	// MemoryPercent triggers a restart once memory use reaches this share
+	// of the container limit. Zero disables the memory rule.
+	MemoryPercent float64
+	// Countdown is used when players are online. Zero means waiting until
+	// the server is empty instead.
+	Countdown time.Duration
+	// Cooldown suppresses new triggers after one was acted upon, so a
+	// restart in progress is not triggered twice.
+	Cooldown time.Duration
+}
+
+func (p Policy) Enabled() bool {
+	return p.MaxUptime > 0 || p.MemoryPercent > 0
+}
+
+type Observation struct {
+	Running       bool
+	StartedAt     time.Time
+	MemoryPercent float64
+	// PlayersKnown is false when the player count could not be confirmed.
+	PlayersKnown bool
+	Players      int
+}
+
+type Action int
+
+const (
+	None Action = iota
+	RestartNow
+	Countdown
+)
+
+func (a Action) String() string {
+	switch a {
+	case None:
+		return "none"
+	case RestartNow:
+		return "restart"
+	case Countdown:
+		return "countdown"
+	default:
+		return "unknown"
+	}
+}
+
+type Decision struct {
+	Action Action
+	Reason string
+	Delay  time.Duration
+}
+
+type Engine struct {
+	policy Policy
+	now    func() time.Time
+
+	mu           sync.Mutex
+	lastTrigger  time.Time
+	triggerStart time.Time
+	// cancelled suppresses triggers after an operator cancelled the
+	// countdown, until the container restarts or the reason clears.
+	cancelled bool
+}
+
+func New(policy Policy, now func() time.Time) *Engine {
+	if now == nil {
+		now = time.Now
+	}
+	if policy.Cooldown <= 0 {
+		policy.Cooldown = 15 * time.Minute
+	}
+	return &Engine{policy: policy, now: now}
+}
+
+// Evaluate decides what to do about one observation. Nothing happens while
+// the player count is unknown: the fail-safe rule of the stop command
+// applies to automatic restarts as well.
+func (e *Engine) Evaluate(obs Observation) Decision {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.now()
+	if !obs.Running || !e.policy.Enabled() {
+		return Decision{Action: None}
+	}
+	reason := e.reason(now, obs)
+	if e.cancelled {
+		if reason != "" && obs.StartedAt.Equal(e.triggerStart) {
+			return Decision{Action: None, Reason: reason}
+		}
+		e.cancelled = false
+	}
+	if !e.lastTrigger.IsZero() && now.Sub(e.lastTrigger) < e.policy.Cooldown {
+		return Decision{Action: None}
+	}
+	if reason == "" || !obs.PlayersKnown {
+		return Decision{Action: None, Reason: reason}
+	}
+
+	switch {
+	case obs.Players == 0:
+		e.trigger(now, obs)
+		return Decision{Action: RestartNow, Reason: reason}
+	case e.policy.Countdown > 0:
+		e.trigger(now, obs)
+		return Decision{Action: Countdown, Reason: reason, Delay: e.policy.Countdown}
+	default:
+		return Decision{Action: None, Reason: reason}
+	}
+}
+
+func (e *Engine) trigger(now time.Time, obs Observation) {
+	e.lastTrigger = now
+	e.triggerStart = obs.StartedAt
+}
+
+// Cancel records that the last triggered restart was cancelled. No new
+// trigger fires until the container has been started again or the reason
+// has cleared and come back.
+func (e *Engine) Cancel() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.lastTrigger.IsZero() {
+		e.cancelled = true
+	}
+}
+
+func (e *Engine) reason(now time.Time, obs Observation) string {
+	if e.policy.MaxUptime > 0 && !obs.StartedAt.IsZero() {
+		if uptime := now.Sub(obs.StartedAt); uptime >= e.policy.MaxUptime {
+			return fmt.Sprintf("uptime %s reached the %s limit", uptime.Round(time.Minute), e.policy.MaxUptime)
+		}
+	}
+	if e.policy.MemoryPercent > 0 && obs.MemoryPercent >= e.policy.MemoryPercent {
+		return fmt.Sprintf("memory at %.1f%% reached the %.0f%% limit", obs.MemoryPercent, e.policy.MemoryPercent)
+	}
+	return ""
+}
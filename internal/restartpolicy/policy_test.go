@@ -0,0 +1,163 @@
+package restartpolicy
+
+import (
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func TestEngineEvaluate(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	policy := Policy{
+		MaxUptime:     12 * time.Hour,
+		MemoryPercent: 80,
+		Countdown:     10 * time.Minute,
+	}
+
+	tests := []struct {
+		name   string
+		policy Policy
+		at     time.Duration
+		obs    Observation
+		want   Action
+	}{
+		{
+			name: "fresh server",
+			at:   time.Hour,
+			obs:  Observation{Running: true, StartedAt: start, MemoryPercent: 40, PlayersKnown: true},
+			want: None,
+		},
+		{
+			name: "uptime reached and empty",
+			at:   12 * time.Hour,
+			obs:  Observation{Running: true, StartedAt: start, MemoryPercent: 40, PlayersKnown: true},
+			want: RestartNow,
+		},
+		{
+			name: "memory reached with players",
+			at:   time.Hour,
+			obs:  Observation{Running: true, StartedAt: start, MemoryPercent: 85, PlayersKnown: true, Players: 2},
+			want: Countdown,
+		},
+		{
+			name: "players unknown never acts",
+			at:   13 * time.Hour,
+			obs:  Observation{Running: true, StartedAt: start, MemoryPercent: 95},
+			want: None,
+		},
+		{
+			name: "stopped server",
+			at:   13 * time.Hour,
+			obs:  Observation{StartedAt: start, MemoryPercent: 95, PlayersKnown: true},
+			want: None,
+		},
+		{
+			name:   "no countdown waits for empty server",
+			policy: Policy{MaxUptime: 12 * time.Hour},
+			at:     13 * time.Hour,
+			obs:    Observation{Running: true, StartedAt: start, PlayersKnown: true, Players: 1},
+			want:   None,
+		},
+		{
+			name:   "disabled policy",
+			policy: Policy{Countdown: time.Minute},
+			at:     100 * time.Hour,
+			obs:    Observation{Running: true, StartedAt: start, MemoryPercent: 99, PlayersKnown: true},
+			want:   None,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.policy
+			if p == (Policy{}) {
+				p = policy
+			}
+			clock := &fakeClock{now: start.Add(tt.at)}
+			got := New(p, clock.Now).Evaluate(tt.obs)
+			if got.Action != tt.want {
+				t.Fatalf("Evaluate() got %v (%q) want %v", got.Action, got.Reason, tt.want)
+			}
+			if got.Action == Countdown && got.Delay != p.Countdown {
+				t.Fatalf("Evaluate() delay got %v want %v", got.Delay, p.Countdown)
+			}
+		})
+	}
+}
+
+func TestEngineCooldown(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: start.Add(12 * time.Hour)}
+	engine := New(Policy{MaxUptime: 12 * time.Hour, Cooldown: 15 * time.Minute}, clock.Now)
+	obs := Observation{Running: true, StartedAt: start, PlayersKnown: true}
+
+	if got := engine.Evaluate(obs).Action; got != RestartNow {
+		t.Fatalf("first Evaluate() got %v want %v", got, RestartNow)
+	}
+	clock.now = clock.now.Add(5 * time.Minute)
+	if got := engine.Evaluate(obs).Action; got != None {
+		t.Fatalf("Evaluate() during cooldown got %v want %v", got, None)
+	}
+	clock.now = clock.now.Add(10 * time.Minute)
+	if got := engine.Evaluate(obs).Action; got != RestartNow {
+		t.Fatalf("Evaluate() after cooldown got %v want %v", got, RestartNow)
+	}
+}
+
+func TestEngineCancel(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: start.Add(13 * time.Hour)}
+	engine := New(Policy{MaxUptime: 12 * time.Hour, MemoryPercent: 90, Countdown: 10 * time.Minute}, clock.Now)
+	obs := Observation{Running: true, StartedAt: start, PlayersKnown: true, Players: 2}
+
+	if got := engine.Evaluate(obs).Action; got != Countdown {
+		t.Fatalf("first Evaluate() got %v want %v", got, Countdown)
+	}
+	engine.Cancel()
+	clock.now = clock.now.Add(time.Hour)
+	if got := engine.Evaluate(obs).Action; got != None {
+		t.Fatalf("Evaluate() after cancel got %v want %v", got, None)
+	}
+
+	// A new container start lifts the suppression.
+	restarted := Observation{Running: true, StartedAt: clock.now, PlayersKnown: true, Players: 2, MemoryPercent: 95}
+	if got := engine.Evaluate(restarted).Action; got != Countdown {
+		t.Fatalf("Evaluate() after container start got %v want %v", got, Countdown)
+	}
+	engine.Cancel()
+	clock.now = clock.now.Add(time.Hour)
+	if got := engine.Evaluate(restarted).Action; got != None {
+		t.Fatalf("Evaluate() after second cancel got %v want %v", got, None)
+	}
+
+	// So does the reason clearing and coming back.
+	restarted.MemoryPercent = 50
+	if got := engine.Evaluate(restarted).Action; got != None {
+		t.Fatalf("Evaluate() below limits got %v want %v", got, None)
+	}
+	restarted.MemoryPercent = 95
+	if got := engine.Evaluate(restarted).Action; got != Countdown {
+		t.Fatalf("Evaluate() once the reason returns got %v want %v", got, Countdown)
+	}
+}
+
+func TestEngineWaitsForEmptyServer(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: start.Add(13 * time.Hour)}
+	engine := New(Policy{MaxUptime: 12 * time.Hour}, clock.Now)
+
+	busy := Observation{Running: true, StartedAt: start, PlayersKnown: true, Players: 3}
+	if d := engine.Evaluate(busy); d.Action != None || d.Reason == "" {
+		t.Fatalf("Evaluate() with players got %+v, want pending reason without action", d)
+	}
+	clock.now = clock.now.Add(time.Minute)
+	busy.Players = 0
+	if got := engine.Evaluate(busy).Action; got != RestartNow {
+		t.Fatalf("Evaluate() once empty got %v want %v", got, RestartNow)
+	}
+}
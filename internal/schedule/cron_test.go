@@ -0,0 +1,83 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr bool
+	}{
+		{spec: "0 18 * * *"},
+		{spec: "*/15 * * * *"},
+		{spec: "0 2 * * 1-5"},
+		{spec: "30 6,18 1,15 * 0,7"},
+		{spec: "0 0 * * 7"},
+		{spec: "0 18 * *", wantErr: true},
+		{spec: "60 * * * *", wantErr: true},
+		{spec: "0 24 * * *", wantErr: true},
+		{spec: "0 0 0 * *", wantErr: true},
+		{spec: "*/0 * * * *", wantErr: true},
+		{spec: "0 5-2 * * *", wantErr: true},
+		{spec: "a b c d e", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			_, err := ParseCron(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCron(%q) err %v wantErr %v", tt.spec, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	// 2024-01-01 is a Monday.
+	base := time.Date(2024, 1, 1, 12, 34, 56, 0, time.UTC)
+	tests := []struct {
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{spec: "0 18 * * *", from: base, want: time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC)},
+		{spec: "0 2 * * *", from: base, want: time.Date(2024, 1, 2, 2, 0, 0, 0, time.UTC)},
+		{spec: "*/15 * * * *", from: base, want: time.Date(2024, 1, 1, 12, 45, 0, 0, time.UTC)},
+		{spec: "0 18 * * 6", from: base, want: time.Date(2024, 1, 6, 18, 0, 0, 0, time.UTC)},
+		{spec: "0 0 * * 7", from: base, want: time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 1 * *", from: base, want: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 29 2 *", from: base, want: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Day of month and day of week restricted: either matches.
+		{spec: "0 12 15 * 3", from: base, want: time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC)},
+		{spec: "0 0 31 2 *", from: base, want: time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			cron, err := ParseCron(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseCron(%q): %v", tt.spec, err)
+			}
+			if got := cron.Next(tt.from); !got.Equal(tt.want) {
+				t.Fatalf("Next(%v) got %v want %v", tt.from, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronMatchesLocalTime(t *testing.T) {
+	loc := time.FixedZone("UTC+5:30", 5*3600+1800)
+	cron, err := ParseCron("0 18 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cron.Matches(time.Date(2024, 1, 1, 18, 0, 0, 0, loc)) {
+		t.Fatal("Matches() must use the wall clock of the given location")
+	}
+	from := time.Date(2024, 1, 1, 17, 10, 0, 0, loc)
+	if got, want := cron.Next(from), time.Date(2024, 1, 1, 18, 0, 0, 0, loc); !got.Equal(want) {
+		t.Fatalf("Next() got %v want %v", got, want)
+	}
+}
@@ -0,0 +1,46 @@
+package schedule
+
+import "time"
+
+// MaxCatchUp bounds how many missed minutes are replayed, so a bot that was
+// suspended for hours does not fire a backlog of stale schedules at once.
+const MaxCatchUp = 15 * time.Minute
+
+// Cursor hands out each wall-clock minute exactly once. Running a schedule
+// can take longer than a minute; the minutes that passed meanwhile are
+// returned by the next Advance instead of being skipped.
+type Cursor struct {
+	now  func() time.Time
+	last time.Time
+}
+
+func NewCursor(now func() time.Time) *Cursor {
+	if now == nil {
+		now = time.Now
+	}
+	return &Cursor{now: now}
+}
+
+// Advance returns the minutes since the previous call, oldest first. The
+// first call returns only the current minute; a clock that went backwards
+// returns nothing until it has caught up again.
+func (c *Cursor) Advance() []time.Time {
+	current := c.now().Truncate(time.Minute)
+	if c.last.IsZero() {
+		c.last = current
+		return []time.Time{current}
+	}
+	from := c.last.Add(time.Minute)
+	if oldest := current.Add(-MaxCatchUp + time.Minute); from.Before(oldest) {
+		from = oldest
+	}
+
+	var minutes []time.Time
+	for minute := from; !minute.After(current); minute = minute.Add(time.Minute) {
+		minutes = append(minutes, minute)
+	}
+	if current.After(c.last) {
+		c.last = current
+	}
+	return minutes
+}
@@ -0,0 +1,49 @@
+package schedule
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func TestCursorAdvance(t *testing.T) {
+	at := func(hour, minute, second int) time.Time {
+		return time.Date(2024, 3, 1, hour, minute, second, 0, time.UTC)
+	}
+	minutes := func(hour, from, to int) []time.Time {
+		var out []time.Time
+		for m := from; m <= to; m++ {
+			out = append(out, at(hour, m, 0))
+		}
+		return out
+	}
+
+	clock := &fakeClock{}
+	cursor := NewCursor(clock.Now)
+	steps := []struct {
+		name string
+		now  time.Time
+		want []time.Time
+	}{
+		{name: "first call", now: at(1, 59, 1), want: minutes(1, 59, 59)},
+		{name: "next minute", now: at(2, 0, 1), want: minutes(2, 0, 0)},
+		{name: "same minute again", now: at(2, 0, 40), want: nil},
+		// A backup that ran from 02:00 to 02:03 must not hide 02:01-02:03.
+		{name: "long running schedule", now: at(2, 3, 30), want: minutes(2, 1, 3)},
+		{name: "clock went backwards", now: at(2, 2, 0), want: nil},
+		{name: "caught up again", now: at(2, 4, 1), want: minutes(2, 4, 4)},
+		{name: "suspended for hours", now: at(5, 30, 1), want: minutes(5, 16, 30)},
+	}
+	for _, step := range steps {
+		clock.now = step.now
+		if got := cursor.Advance(); !reflect.DeepEqual(got, step.want) {
+			t.Fatalf("%s: Advance() got %v want %v", step.name, got, step.want)
+		}
+	}
+}
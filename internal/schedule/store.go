@@ -0,0 +1,169 @@
+package schedule
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"pikabot/internal/fsutil"
+)
+
+type Action string
+
+const (
+	Start  Action = "start"
+	Stop   Action = "stop"
+	Backup Action = "backup"
+)
+
+func ParseAction(value string) (Action, error) {
+	switch Action(strings.ToLower(value)) {
+	case Start:
+		return Start, nil
+	case Stop:
+		return Stop, nil
+	case Backup:
+		return Backup, nil
+	default:
+		return "", fmt.Errorf("unknown schedule action %q (use start, stop or backup)", value)
+	}
+}
+
+type Entry struct {
+	ID     string `json:"id"`
+	Action Action `json:"action"`
+	Spec   string `json:"spec"`
+	// Fixed entries come from configuration and cannot be removed from chat.
+	Fixed bool `json:"-"`
+
+	cron Cron
+}
+
+func (e Entry) Cron() Cron {
+	return e.cron
+}
+
+type Store struct {
+	mu      sync.Mutex
+	path    string
+	fixed   []Entry
+	entries []Entry
+}
+
+// NewStore loads persisted entries from path. fixed entries are listed and
+// run alongside them but never written to disk.
+func NewStore(path string, fixed []Entry) (*Store, error) {
+	s := &Store{path: path}
+	for i, entry := range fixed {
+		cron, err := ParseCron(entry.Spec)
+		if err != nil {
+			return nil, err
+		}
+		entry.cron = cron
+		entry.Fixed = true
+		if entry.ID == "" {
+			entry.ID = "config-" + strconv.Itoa(i+1)
+		}
+		s.fixed = append(s.fixed, entry)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("read schedules: %w", err)
+	}
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parse schedules %s: %w", path, err)
+	}
+	for _, entry := range entries {
+		cron, err := ParseCron(entry.Spec)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %s: %w", entry.ID, err)
+		}
+		entry.cron = cron
+		s.entries = append(s.entries, entry)
+	}
+	return s, nil
+}
+
+func (s *Store) List() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Entry, 0, len(s.fixed)+len(s.entries))
+	out = append(out, s.fixed...)
+	out = append(out, s.entries...)
+	return out
+}
+
+func (s *Store) Add(action Action, spec string) (Entry, error) {
+	cron, err := ParseCron(spec)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := Entry{ID: s.nextID(), Action: action, Spec: cron.String(), cron: cron}
+	entries := append(append([]Entry(nil), s.entries...), entry)
+	if err := s.save(entries); err != nil {
+		return Entry{}, err
+	}
+	s.entries = entries
+	return entry, nil
+}
+
+func (s *Store) Remove(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, entry := range s.fixed {
+		if entry.ID == id {
+			return fmt.Errorf("schedule %s comes from configuration and cannot be removed", id)
+		}
+	}
+	entries := make([]Entry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		if entry.ID != id {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == len(s.entries) {
+		return fmt.Errorf("schedule %s not found", id)
+	}
+	if err := s.save(entries); err != nil {
+		return err
+	}
+	s.entries = entries
+	return nil
+}
+
+func (s *Store) nextID() string {
+	highest := 0
+	for _, entry := range s.entries {
+		if n, err := strconv.Atoi(entry.ID); err == nil && n > highest {
+			highest = n
+		}
+	}
+	return strconv.Itoa(highest + 1)
+}
+
+func (s *Store) save(entries []Entry) error {
+	sorted := append([]Entry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, _ := strconv.Atoi(sorted[i].ID)
+		b, _ := strconv.Atoi(sorted[j].ID)
+		return a < b
+	})
+	data, err := json.MarshalIndent(sorted, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fsutil.WriteFileAtomic(s.path, append(data, '\n'), 0o600)
+}
@@ -0,0 +1,61 @@
+package schedule
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestStorePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedules.json")
+	fixed := []Entry{{Action: Start, Spec: "0 18 * * *"}}
+
+	store, err := NewStore(path, fixed)
+	if err != nil {
+		t.Fatalf("NewStore(): %v", err)
+	}
+	first, err := store.Add(Stop, "0  2 * * *")
+	if err != nil {
+		t.Fatalf("Add(): %v", err)
+	}
+	if first.ID != "1" || first.Spec != "0 2 * * *" {
+		t.Fatalf("Add() got %+v, want id 1 with normalized spec", first)
+	}
+	if _, err := store.Add(Start, "bogus"); err == nil {
+		t.Fatal("Add() with invalid spec expected error")
+	}
+	if _, err := store.Add(Start, "0 20 * * 5"); err != nil {
+		t.Fatalf("Add(): %v", err)
+	}
+
+	reloaded, err := NewStore(path, fixed)
+	if err != nil {
+		t.Fatalf("NewStore() reload: %v", err)
+	}
+	entries := reloaded.List()
+	if len(entries) != 3 {
+		t.Fatalf("List() got %d entries want 3: %+v", len(entries), entries)
+	}
+	if !entries[0].Fixed || entries[0].ID != "config-1" {
+		t.Fatalf("List()[0] got %+v, want fixed config entry first", entries[0])
+	}
+	if entries[1].Action != Stop || entries[2].ID != "2" {
+		t.Fatalf("List() got %+v", entries)
+	}
+
+	if err := reloaded.Remove("config-1"); err == nil {
+		t.Fatal("Remove() of a configured entry expected error")
+	}
+	if err := reloaded.Remove("9"); err == nil {
+		t.Fatal("Remove() of an unknown entry expected error")
+	}
+	if err := reloaded.Remove("1"); err != nil {
+		t.Fatalf("Remove(): %v", err)
+	}
+	next, err := reloaded.Add(Stop, "0 3 * * *")
+	if err != nil {
+		t.Fatalf("Add(): %v", err)
+	}
+	if next.ID != "3" {
+		t.Fatalf("Add() after remove got id %s want 3", next.ID)
+	}
+}
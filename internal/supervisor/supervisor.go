@@ -0,0 +1,176 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+type Config struct {
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+	MaxCrashes int
+	Window     time.Duration
+}
+
+type Decision struct {
+	// GiveUp is set once MaxCrashes crashes happened within Window; no
+	// further restarts are attempted until Reset.
+	GiveUp  bool
+	Delay   time.Duration
+	Crashes int
+
+	// epoch ties the decision to the crash it was made for; Cancel and
+	// Reset move the epoch on so a restart still waiting goes stale.
+	epoch uint64
+}
+
+var (
+	ErrGivenUp   = errors.New("crash loop detected; auto-restart disabled")
+	ErrCancelled = errors.New("auto-restart cancelled")
+)
+
+// Supervisor decides when a crashed container is restarted, with exponential
+// backoff, and stops trying when crashes cluster into a crash loop. Starting
+// the container is left to the caller, which has to coordinate with manual
+// commands.
+type Supervisor struct {
+	cfg   Config
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) bool
+
+	mu      sync.Mutex
+	crashes []time.Time
+	givenUp bool
+	pending bool
+	epoch   uint64
+}
+
+func New(cfg Config) *Supervisor {
+	if cfg.BaseDelay <= 0 {
+		cfg.BaseDelay = 10 * time.Second
+	}
+	if cfg.MaxDelay < cfg.BaseDelay {
+		cfg.MaxDelay = cfg.BaseDelay
+	}
+	if cfg.MaxCrashes <= 0 {
+		cfg.MaxCrashes = 3
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = 30 * time.Minute
+	}
+	return &Supervisor{cfg: cfg, now: time.Now, sleep: sleepContext}
+}
+
+// RecordCrash registers an unexpected exit and decides how to react.
+func (s *Supervisor) RecordCrash() Decision {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	recent := s.crashes[:0]
+	for _, at := range s.crashes {
+		if now.Sub(at) < s.cfg.Window {
+			recent = append(recent, at)
+		}
+	}
+	s.crashes = append(recent, now)
+	crashes := len(s.crashes)
+
+	s.epoch++
+	if s.givenUp || crashes >= s.cfg.MaxCrashes {
+		s.givenUp = true
+		s.pending = false
+		return Decision{GiveUp: true, Crashes: crashes, epoch: s.epoch}
+	}
+	s.pending = true
+	return Decision{Delay: s.backoff(crashes), Crashes: crashes, epoch: s.epoch}
+}
+
+// Wait waits out the decided delay. It returns ErrGivenUp if the supervisor
+// gave up in the meantime and ErrCancelled if the restart was cancelled or
+// superseded by a newer crash.
+func (s *Supervisor) Wait(ctx context.Context, d Decision) error {
+	if d.GiveUp {
+		return ErrGivenUp
+	}
+	if !s.sleep(ctx, d.Delay) {
+		return ctx.Err()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.check(d)
+}
+
+// Claim takes the restart for d right before the caller starts the
+// container, so it runs at most once. It fails like Wait when the restart is
+// no longer wanted.
+func (s *Supervisor) Claim(d Decision) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.check(d); err != nil {
+		return err
+	}
+	s.pending = false
+	return nil
+}
+
+// Cancel drops a restart that is still waiting, e.g. because the server was
+// started, stopped or restored by hand. It reports whether one was pending.
+func (s *Supervisor) Cancel() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pending := s.pending
+	s.pending = false
+	s.epoch++
+	return pending
+}
+
+func (s *Supervisor) check(d Decision) error {
+	if d.GiveUp || s.givenUp {
+		return ErrGivenUp
+	}
+	if !s.pending || d.epoch != s.epoch {
+		return ErrCancelled
+	}
+	return nil
+}
+
+func (s *Supervisor) GivenUp() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.givenUp
+}
+
+// Reset forgets the crash history, e.g. after a manual start.
+func (s *Supervisor) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.crashes = nil
+	s.givenUp = false
+	s.pending = false
+	s.epoch++
+}
+
+func (s *Supervisor) backoff(crashes int) time.Duration {
+	delay := s.cfg.BaseDelay
+	for i := 1; i < crashes; i++ {
+		delay *= 2
+		if delay >= s.cfg.MaxDelay {
+			return s.cfg.MaxDelay
+		}
+	}
+	return delay
+}
+
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,178 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now    time.Time
+	slept  []time.Duration
+	cancel bool
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Sleep(_ context.Context, d time.Duration) bool {
+	c.slept = append(c.slept, d)
+	c.now = c.now.Add(d)
+	return !c.cancel
+}
+
+func newTestSupervisor(clock *fakeClock) *Supervisor {
+	s := New(Config{
+		BaseDelay:  10 * time.Second,
+		MaxDelay:   time.Minute,
+		MaxCrashes: 4,
+		Window:     30 * time.Minute,
+	})
+	s.now = clock.Now
+	s.sleep = clock.Sleep
+	return s
+}
+
+// restart runs one restart the way the bot does and reports whether the
+// container would have been started.
+func restart(ctx context.Context, s *Supervisor, d Decision) (bool, error) {
+	if err := s.Wait(ctx, d); err != nil {
+		return false, err
+	}
+	if err := s.Claim(d); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func TestSupervisorBackoff(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	s := newTestSupervisor(clock)
+
+	for i := 0; i < 3; i++ {
+		d := s.RecordCrash()
+		if d.GiveUp {
+			t.Fatalf("crash %d: unexpected give up", i+1)
+		}
+		if started, err := restart(context.Background(), s, d); !started || err != nil {
+			t.Fatalf("crash %d: restart got %v, %v want started", i+1, started, err)
+		}
+		clock.now = clock.now.Add(time.Minute)
+	}
+
+	want := []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second}
+	if !reflect.DeepEqual(clock.slept, want) {
+		t.Fatalf("delays got %v want %v", clock.slept, want)
+	}
+}
+
+func TestSupervisorBackoffCapped(t *testing.T) {
+	s := newTestSupervisor(&fakeClock{})
+	if got := s.backoff(10); got != time.Minute {
+		t.Fatalf("backoff(10) got %v want %v", got, time.Minute)
+	}
+}
+
+func TestSupervisorCrashLoop(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	s := newTestSupervisor(clock)
+
+	var last Decision
+	starts := 0
+	for i := 0; i < 4; i++ {
+		last = s.RecordCrash()
+		if started, _ := restart(context.Background(), s, last); started {
+			starts++
+		}
+		clock.now = clock.now.Add(time.Minute)
+	}
+	if !last.GiveUp || last.Crashes != 4 {
+		t.Fatalf("4th crash got %+v, want give up after 4 crashes", last)
+	}
+	if starts != 3 {
+		t.Fatalf("starts got %d want 3", starts)
+	}
+
+	// Stays given up until reset, even once the window has passed.
+	clock.now = clock.now.Add(time.Hour)
+	if d := s.RecordCrash(); !d.GiveUp {
+		t.Fatalf("crash after give up got %+v, want give up", d)
+	}
+	if err := s.Wait(context.Background(), Decision{Delay: time.Second}); !errors.Is(err, ErrGivenUp) {
+		t.Fatalf("Wait() after give up err %v want %v", err, ErrGivenUp)
+	}
+
+	s.Reset()
+	if d := s.RecordCrash(); d.GiveUp || d.Delay != 10*time.Second {
+		t.Fatalf("crash after reset got %+v, want first backoff", d)
+	}
+}
+
+func TestSupervisorWindowExpires(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	s := newTestSupervisor(clock)
+
+	for i := 0; i < 10; i++ {
+		d := s.RecordCrash()
+		if d.GiveUp {
+			t.Fatalf("crash %d: crashes spread over hours must not count as a loop", i+1)
+		}
+		if d.Crashes != 1 || d.Delay != 10*time.Second {
+			t.Fatalf("crash %d: got %+v, want a fresh first crash", i+1, d)
+		}
+		clock.now = clock.now.Add(time.Hour)
+	}
+}
+
+func TestSupervisorWaitCancelled(t *testing.T) {
+	clock := &fakeClock{}
+	s := newTestSupervisor(clock)
+
+	clock.cancel = true
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := s.Wait(ctx, s.RecordCrash()); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait() cancelled err %v want %v", err, context.Canceled)
+	}
+}
+
+func TestSupervisorCancelDropsPendingRestart(t *testing.T) {
+	s := newTestSupervisor(&fakeClock{})
+	ctx := context.Background()
+
+	if s.Cancel() {
+		t.Fatal("Cancel() without a crash reported a pending restart")
+	}
+
+	d := s.RecordCrash()
+	if !s.Cancel() {
+		t.Fatal("Cancel() did not report the pending restart")
+	}
+	if err := s.Wait(ctx, d); !errors.Is(err, ErrCancelled) {
+		t.Fatalf("Wait() after Cancel err %v want %v", err, ErrCancelled)
+	}
+
+	// A manual command between Wait and Claim still wins.
+	d = s.RecordCrash()
+	if err := s.Wait(ctx, d); err != nil {
+		t.Fatalf("Wait() err %v", err)
+	}
+	s.Reset()
+	if err := s.Claim(d); !errors.Is(err, ErrCancelled) {
+		t.Fatalf("Claim() after Reset err %v want %v", err, ErrCancelled)
+	}
+
+	// A newer crash supersedes the older decision, and a claim runs once.
+	stale := s.RecordCrash()
+	d = s.RecordCrash()
+	if err := s.Claim(stale); !errors.Is(err, ErrCancelled) {
+		t.Fatalf("Claim() of superseded decision err %v want %v", err, ErrCancelled)
+	}
+	if err := s.Claim(d); err != nil {
+		t.Fatalf("Claim() err %v", err)
+	}
+	if err := s.Claim(d); !errors.Is(err, ErrCancelled) {
+		t.Fatalf("second Claim() err %v want %v", err, ErrCancelled)
+	}
+}